@@ -0,0 +1,299 @@
+package goss
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type (
+	CascadeDNSRecord struct {
+		DomainName string
+		Record     *DomainRecordResponse
+	}
+
+	// FirewallRules are the Allow rules opening the public side of removed
+	// port forwards, as created by ExposeService.
+	CascadeNATRules struct {
+		GatewayID     string
+		Removed       []*NATRule
+		FirewallRules []*FirewallRule
+	}
+
+	ServerCascadePlan struct {
+		Server     *ServerResponse
+		DNSRecords []*CascadeDNSRecord
+		NATRules   []*CascadeNATRules
+		Snapshots  []*SnapshotEntity
+		Volumes    []*VolumeEntity
+		NICS       []*NICEntity
+	}
+)
+
+func (c *SSClient) PlanServerCascade(serverID string) (*ServerCascadePlan, error) {
+	server, err := c.GetServer(serverID)
+	if err != nil {
+		return nil, err
+	}
+	plan := &ServerCascadePlan{Server: server}
+
+	plan.NICS, err = c.GetNICList(serverID)
+	if err != nil {
+		return nil, err
+	}
+	publicIPs := make(map[string]bool)
+	networkIPs := make(map[string]map[string]bool)
+	for _, nic := range plan.NICS {
+		if nic.IPAddress == "" {
+			continue
+		}
+		if nic.NetworkType == PublicSharedNetwork {
+			publicIPs[nic.IPAddress] = true
+			continue
+		}
+		if networkIPs[nic.NetworkID] == nil {
+			networkIPs[nic.NetworkID] = make(map[string]bool)
+		}
+		networkIPs[nic.NetworkID][nic.IPAddress] = true
+	}
+
+	// The first volume is the system one and goes away together with the server.
+	if len(server.Volumes) > 1 {
+		plan.Volumes = server.Volumes[1:]
+	}
+
+	plan.Snapshots, err = c.GetSnapshotList(serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	gateways, err := c.GetGatewayList()
+	if err != nil {
+		return nil, err
+	}
+	for _, gateway := range gateways {
+		ips := make(map[string]bool)
+		for _, networkID := range gateway.NetworkIDs {
+			for ip := range networkIPs[networkID] {
+				ips[ip] = true
+			}
+		}
+		if len(ips) == 0 {
+			continue
+		}
+		rules, err := c.GetNATRules(gateway.ID)
+		if err != nil {
+			return nil, err
+		}
+		gatewayRules := &CascadeNATRules{GatewayID: gateway.ID}
+		var remaining []*NATRule
+		for _, rule := range rules {
+			if ips[rule.Translated] || ips[rule.Source] {
+				gatewayRules.Removed = append(gatewayRules.Removed, rule)
+			} else {
+				remaining = append(remaining, rule)
+			}
+		}
+		if len(gatewayRules.Removed) == 0 {
+			continue
+		}
+		if err := c.planExposedCleanup(gatewayRules, remaining, publicIPs); err != nil {
+			return nil, err
+		}
+		plan.NATRules = append(plan.NATRules, gatewayRules)
+	}
+
+	if len(publicIPs) == 0 {
+		return plan, nil
+	}
+	domains, err := c.GetDomainList()
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		records, err := c.GetRecordList(domain.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			if record.Type != ARecordType && record.Type != AAAARecordType {
+				continue
+			}
+			if record.IP != nil && publicIPs[*record.IP] {
+				plan.DNSRecords = append(plan.DNSRecords, &CascadeDNSRecord{
+					DomainName: domain.Name,
+					Record:     record,
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// planExposedCleanup adds the firewall rules opening removed port forwards to
+// the plan. The gateway public address is added to publicIPs, so its DNS
+// records are removed, once no remaining port forward uses it.
+func (c *SSClient) planExposedCleanup(gatewayRules *CascadeNATRules, remaining []*NATRule, publicIPs map[string]bool) error {
+	var forwards []*NATRule
+	for _, rule := range gatewayRules.Removed {
+		if rule.RuleType == NATRuleTypeDNAT {
+			forwards = append(forwards, rule)
+		}
+	}
+	if len(forwards) == 0 {
+		return nil
+	}
+
+	firewallRules, err := c.GetFirewallRules(gatewayRules.GatewayID)
+	if err != nil {
+		return err
+	}
+	for _, firewallRule := range firewallRules {
+		for _, forward := range forwards {
+			if exposesForward(firewallRule, forward) {
+				gatewayRules.FirewallRules = append(gatewayRules.FirewallRules, firewallRule)
+				break
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, rule := range remaining {
+		if rule.RuleType == NATRuleTypeDNAT {
+			used[normalizeRuleAddress(rule.Destination)] = true
+		}
+	}
+	for _, forward := range forwards {
+		if publicIP := normalizeRuleAddress(forward.Destination); publicIP != "" && !used[publicIP] {
+			publicIPs[publicIP] = true
+		}
+	}
+	return nil
+}
+
+func exposesForward(firewallRule *FirewallRule, forward *NATRule) bool {
+	return firewallRule.Action == FirewallActionAllow &&
+		firewallRule.Direction == FirewallDirectionIn &&
+		normalizeProtocol(firewallRule.Protocol) == normalizeProtocol(forward.Protocol) &&
+		normalizeRuleAddress(firewallRule.Destination) == normalizeRuleAddress(forward.Destination) &&
+		portRangesEqual(firewallRule.DestinationPort, Port(forward.DestinationPort))
+}
+
+func (p *ServerCascadePlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Destroy server '%s' (%s):\n", p.Server.Name, p.Server.ID)
+	for _, dns := range p.DNSRecords {
+		fmt.Fprintf(
+			&b, "  - delete DNS record %s %s -> %s (domain %s)\n",
+			dns.Record.Type, dns.Record.Name, *dns.Record.IP, dns.DomainName,
+		)
+	}
+	for _, nat := range p.NATRules {
+		for _, rule := range nat.Removed {
+			fmt.Fprintf(&b, "  - delete NAT rule %s (gateway %s)\n", rule, nat.GatewayID)
+		}
+		for _, rule := range nat.FirewallRules {
+			fmt.Fprintf(&b, "  - delete firewall rule %s (gateway %s)\n", rule, nat.GatewayID)
+		}
+	}
+	for _, snapshot := range p.Snapshots {
+		fmt.Fprintf(&b, "  - delete snapshot '%s' (%d)\n", snapshot.Name, snapshot.ID)
+	}
+	for _, volume := range p.Volumes {
+		fmt.Fprintf(&b, "  - delete volume '%s' (%d, %d MB)\n", volume.Name, volume.ID, volume.Size)
+	}
+	for _, nic := range p.NICS {
+		fmt.Fprintf(&b, "  - delete NIC %d (%s, network %s)\n", nic.ID, nic.IPAddress, nic.NetworkID)
+	}
+	fmt.Fprintf(&b, "  - delete server '%s' (%s)\n", p.Server.Name, p.Server.ID)
+	return b.String()
+}
+
+func (c *SSClient) DestroyServerCascade(serverID string, dryRun bool) (*ServerCascadePlan, error) {
+	plan, err := c.PlanServerCascade(serverID)
+	if err != nil {
+		return nil, err
+	}
+	log.Default().Printf("[INFO] %s", plan)
+	if dryRun {
+		return plan, nil
+	}
+	return plan, c.ApplyServerCascade(plan)
+}
+
+func (c *SSClient) ApplyServerCascade(plan *ServerCascadePlan) error {
+	serverID := plan.Server.ID
+
+	for _, dns := range plan.DNSRecords {
+		if err := c.DeleteRecord(dns.DomainName, strconv.Itoa(dns.Record.ID)); err != nil {
+			return err
+		}
+	}
+	for _, nat := range plan.NATRules {
+		removed := nat.Removed
+		_, err := c.updateNATRules(nat.GatewayID, func(rules []*NATRule) ([]*NATRule, error) {
+			remaining := rules[:0]
+			for _, rule := range rules {
+				if !natRuleIn(rule, removed) {
+					remaining = append(remaining, rule)
+				}
+			}
+			return remaining, nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(nat.FirewallRules) == 0 {
+			continue
+		}
+		firewallRules := nat.FirewallRules
+		_, err = c.updateFirewallRules(nat.GatewayID, func(rules []*FirewallRule) ([]*FirewallRule, error) {
+			remaining := rules[:0]
+			for _, rule := range rules {
+				if !firewallRuleIn(rule, firewallRules) {
+					remaining = append(remaining, rule)
+				}
+			}
+			return remaining, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, snapshot := range plan.Snapshots {
+		if err := c.DeleteSnapshotAndWait(serverID, snapshot.ID); err != nil {
+			return err
+		}
+	}
+	for _, volume := range plan.Volumes {
+		if err := c.DeleteVolumeAndWait(serverID, volume.ID); err != nil {
+			return err
+		}
+	}
+	for _, nic := range plan.NICS {
+		if err := c.DeleteNICAndWait(serverID, nic.ID); err != nil {
+			return err
+		}
+	}
+	return c.DeleteServerAndWait(serverID)
+}
+
+func firewallRuleIn(rule *FirewallRule, rules []*FirewallRule) bool {
+	for _, other := range rules {
+		if rule.Equal(other) {
+			return true
+		}
+	}
+	return false
+}
+
+func natRuleIn(rule *NATRule, rules []*NATRule) bool {
+	for _, other := range rules {
+		if rule.Equal(other) {
+			return true
+		}
+	}
+	return false
+}
@@ -32,6 +32,8 @@ type SSClient struct {
 	Key       string
 	Host      string
 	UserAgent *string
+
+	locationCache locationCatalog
 }
 
 func NewClient(key string, host string, agent *string) (*SSClient, error) {
@@ -61,7 +63,12 @@ func NewClient(key string, host string, agent *string) (*SSClient, error) {
 	baseURL := fmt.Sprintf("%s/%s", host, "api/v1/")
 	client.SetBaseURL(baseURL)
 
-	c := &SSClient{client, key, host, &userAgentHeader}
+	c := &SSClient{
+		client:    client,
+		Key:       key,
+		Host:      host,
+		UserAgent: &userAgentHeader,
+	}
 
 	return c, nil
 }
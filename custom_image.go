@@ -0,0 +1,330 @@
+package goss
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	customImageBaseURL                = "images/custom"
+	imageUploadBaseURL                = "images/custom/uploads"
+	defaultImageUploadChunkSize int64 = 16 << 20
+)
+
+type CustomImageType string
+
+const (
+	CustomImageTypeImage CustomImageType = "Image"
+	CustomImageTypeISO   CustomImageType = "ISO"
+)
+
+type (
+	CustomImageEntity struct {
+		ID         string          `json:"id,omitempty"`
+		Name       string          `json:"name,omitempty"`
+		LocationID string          `json:"location_id,omitempty"`
+		Type       CustomImageType `json:"type,omitempty"`
+		SizeMB     int             `json:"size_mb,omitempty"`
+		State      string          `json:"state,omitempty"`
+		Created    string          `json:"created,omitempty"`
+	}
+
+	ImageUploadSession struct {
+		ID        string `json:"id,omitempty"`
+		Size      int64  `json:"size,omitempty"`
+		Offset    int64  `json:"offset,omitempty"`
+		ChunkSize int64  `json:"chunk_size,omitempty"`
+	}
+
+	// SessionID resumes a previously interrupted upload, see ImageUploadError.
+	ImageUploadOptions struct {
+		Name       string
+		LocationID string
+		Type       CustomImageType
+		ChunkSize  int64
+		SessionID  string
+		Progress   func(uploaded, total int64)
+	}
+
+	customImageResponseWrap struct {
+		CustomImage *CustomImageEntity `json:"custom_image,omitempty"`
+	}
+
+	customImageListResponseWrap struct {
+		CustomImages []*CustomImageEntity `json:"custom_images,omitempty"`
+	}
+
+	imageUploadResponseWrap struct {
+		Upload *ImageUploadSession `json:"upload,omitempty"`
+	}
+)
+
+func (c *SSClient) GetCustomImage(imageID string) (*CustomImageEntity, error) {
+	url := getCustomImageURL(imageID)
+	resp, err := makeRequest(c.client, url, methodGet, nil, &customImageResponseWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*customImageResponseWrap).CustomImage, nil
+}
+
+func (c *SSClient) GetCustomImageList() ([]*CustomImageEntity, error) {
+	resp, err := makeRequest(c.client, customImageBaseURL, methodGet, nil, &customImageListResponseWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*customImageListResponseWrap).CustomImages, nil
+}
+
+func (c *SSClient) CreateImageFromServer(serverID, name string) (*TaskIDWrap, error) {
+	payload := map[string]interface{}{
+		"name":      name,
+		"server_id": serverID,
+	}
+	resp, err := makeRequest(c.client, customImageBaseURL, methodPost, payload, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+func (c *SSClient) CreateImageFromServerAndWait(serverID, name string) (*CustomImageEntity, error) {
+	taskWrap, err := c.CreateImageFromServer(serverID, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitCustomImage(taskWrap.ID)
+}
+
+func (c *SSClient) CreateImageFromSnapshot(serverID string, snapshotID int, name string) (*TaskIDWrap, error) {
+	payload := map[string]interface{}{
+		"name":        name,
+		"server_id":   serverID,
+		"snapshot_id": snapshotID,
+	}
+	resp, err := makeRequest(c.client, customImageBaseURL, methodPost, payload, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+func (c *SSClient) CreateImageFromSnapshotAndWait(
+	serverID string,
+	snapshotID int,
+	name string,
+) (*CustomImageEntity, error) {
+	taskWrap, err := c.CreateImageFromSnapshot(serverID, snapshotID, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitCustomImage(taskWrap.ID)
+}
+
+func (c *SSClient) DeleteCustomImage(imageID string) (*TaskIDWrap, error) {
+	url := getCustomImageURL(imageID)
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+func (c *SSClient) DeleteCustomImageAndWait(imageID string) error {
+	taskWrap, err := c.DeleteCustomImage(imageID)
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetCustomImage(imageID)
+		return err
+	})
+}
+
+func (c *SSClient) UploadImage(path string, opts *ImageUploadOptions) (*CustomImageEntity, error) {
+	if opts == nil {
+		opts = &ImageUploadOptions{}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var session *ImageUploadSession
+	if opts.SessionID != "" {
+		session, err = c.getImageUpload(opts.SessionID)
+	} else {
+		session, err = c.createImageUpload(path, info.Size(), opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("no upload session returned for '%s'", path)
+	}
+	if session.Size != 0 && session.Size != info.Size() {
+		return nil, fmt.Errorf(
+			"upload '%s' expects %d bytes but '%s' has %d bytes",
+			session.ID, session.Size, path, info.Size(),
+		)
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = session.ChunkSize
+	}
+	if chunkSize <= 0 {
+		chunkSize = defaultImageUploadChunkSize
+	}
+
+	total := info.Size()
+	offset := session.Offset
+	buf := make([]byte, chunkSize)
+	for offset < total {
+		if opts.Progress != nil {
+			opts.Progress(offset, total)
+		}
+		n, err := file.ReadAt(buf, offset)
+		if err != nil && err != io.EOF {
+			return nil, NewImageUploadError(session.ID, offset, err)
+		}
+		if n == 0 {
+			return nil, NewImageUploadError(
+				session.ID, offset, fmt.Errorf("'%s' ended at %d bytes, expected %d", path, offset, total),
+			)
+		}
+		uploaded, err := c.uploadImageChunk(session.ID, buf[:n], offset, total)
+		if err != nil {
+			return nil, NewImageUploadError(session.ID, offset, err)
+		}
+		if uploaded == nil {
+			offset += int64(n)
+			continue
+		}
+		if uploaded.Offset <= offset {
+			return nil, NewImageUploadError(session.ID, offset, fmt.Errorf("upload didn't advance"))
+		}
+		offset = uploaded.Offset
+	}
+	if opts.Progress != nil {
+		opts.Progress(total, total)
+	}
+
+	url := fmt.Sprintf("%s/%s/complete", imageUploadBaseURL, session.ID)
+	resp, err := makeRequest(c.client, url, methodPost, nil, &TaskIDWrap{})
+	if err != nil {
+		return nil, NewImageUploadError(session.ID, offset, err)
+	}
+	return c.waitCustomImage(resp.(*TaskIDWrap).ID)
+}
+
+// CreateServerFromCustomImage checks that the custom image can boot a server
+// in locationID and passes its ID as the image ID to CreateServer.
+func (c *SSClient) CreateServerFromCustomImage(
+	name string,
+	locationID string,
+	customImageID string,
+	cpu int,
+	ram int,
+	volumes []*VolumeData,
+	networks []*NetworkData,
+	sshKeyIds []int,
+) (*TaskIDWrap, error) {
+	image, err := c.GetCustomImage(customImageID)
+	if err != nil {
+		return nil, err
+	}
+	if image.Type == CustomImageTypeISO {
+		return nil, fmt.Errorf("custom image '%s' is an ISO and can't be used as a server image", customImageID)
+	}
+	if image.LocationID != "" && image.LocationID != locationID {
+		return nil, fmt.Errorf(
+			"custom image '%s' is stored in location '%s', not '%s'",
+			customImageID, image.LocationID, locationID,
+		)
+	}
+	return c.CreateServer(name, locationID, image.ID, cpu, ram, volumes, networks, sshKeyIds)
+}
+
+func (c *SSClient) CreateServerFromCustomImageAndWait(
+	name string,
+	locationID string,
+	customImageID string,
+	cpu int,
+	ram int,
+	volumes []*VolumeData,
+	networks []*NetworkData,
+	sshKeyIds []int,
+) (*ServerResponse, error) {
+	taskWrap, err := c.CreateServerFromCustomImage(
+		name, locationID, customImageID, cpu, ram, volumes, networks, sshKeyIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitServer(taskWrap.ID)
+}
+
+func (c *SSClient) createImageUpload(path string, size int64, opts *ImageUploadOptions) (*ImageUploadSession, error) {
+	name := opts.Name
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	imageType := opts.Type
+	if imageType == "" {
+		imageType = CustomImageTypeImage
+	}
+	payload := map[string]interface{}{
+		"name":        name,
+		"location_id": opts.LocationID,
+		"type":        imageType,
+		"size":        size,
+	}
+	resp, err := makeRequest(c.client, imageUploadBaseURL, methodPost, payload, &imageUploadResponseWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*imageUploadResponseWrap).Upload, nil
+}
+
+func (c *SSClient) getImageUpload(sessionID string) (*ImageUploadSession, error) {
+	url := fmt.Sprintf("%s/%s", imageUploadBaseURL, sessionID)
+	resp, err := makeRequest(c.client, url, methodGet, nil, &imageUploadResponseWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*imageUploadResponseWrap).Upload, nil
+}
+
+func (c *SSClient) uploadImageChunk(sessionID string, chunk []byte, offset, total int64) (*ImageUploadSession, error) {
+	url := fmt.Sprintf("%s/%s", imageUploadBaseURL, sessionID)
+	headers := map[string]string{
+		"Content-Type":  "application/octet-stream",
+		"Content-Range": fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, total),
+	}
+	resp, err := makeRawRequest(c.client, url, methodPut, headers, chunk, &imageUploadResponseWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*imageUploadResponseWrap).Upload, nil
+}
+
+func (c *SSClient) waitCustomImage(taskID string) (*CustomImageEntity, error) {
+	task, err := c.waitTaskCompletion(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCustomImage(task.ImageID)
+}
+
+func getCustomImageURL(imageID string) string {
+	return fmt.Sprintf("%s/%s", customImageBaseURL, imageID)
+}
@@ -0,0 +1,306 @@
+package goss
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type (
+	FirewallRuleChange struct {
+		From *FirewallRule `json:"from"`
+		To   *FirewallRule `json:"to"`
+	}
+
+	FirewallRuleDiff struct {
+		Added     []*FirewallRule       `json:"added,omitempty"`
+		Removed   []*FirewallRule       `json:"removed,omitempty"`
+		Changed   []*FirewallRuleChange `json:"changed,omitempty"`
+		Reordered bool                  `json:"reordered,omitempty"`
+	}
+
+	NATRuleChange struct {
+		From *NATRule `json:"from"`
+		To   *NATRule `json:"to"`
+	}
+
+	NATRuleDiff struct {
+		Added   []*NATRule       `json:"added,omitempty"`
+		Removed []*NATRule       `json:"removed,omitempty"`
+		Changed []*NATRuleChange `json:"changed,omitempty"`
+	}
+
+	FirewallPlan struct {
+		GatewayID string
+		Current   []*FirewallRule
+		Desired   []*FirewallRule
+		Diff      *FirewallRuleDiff
+	}
+)
+
+// Firewall rules are evaluated top to bottom, so besides added, removed
+// and changed rules the diff reports whether the common rules were reordered.
+func DiffFirewallRules(current, desired []*FirewallRule) *FirewallRuleDiff {
+	diff := &FirewallRuleDiff{}
+
+	currentLeft := make(map[string]int)
+	for _, rule := range current {
+		currentLeft[firewallRuleKey(rule)]++
+	}
+	desiredLeft := make(map[string]int)
+	for _, rule := range desired {
+		desiredLeft[firewallRuleKey(rule)]++
+	}
+
+	var removed, added, currentCommon, desiredCommon []*FirewallRule
+	for _, rule := range current {
+		key := firewallRuleKey(rule)
+		if desiredLeft[key] > 0 {
+			desiredLeft[key]--
+			currentCommon = append(currentCommon, rule)
+		} else {
+			removed = append(removed, rule)
+		}
+	}
+	for _, rule := range desired {
+		key := firewallRuleKey(rule)
+		if currentLeft[key] > 0 {
+			currentLeft[key]--
+			desiredCommon = append(desiredCommon, rule)
+		} else {
+			added = append(added, rule)
+		}
+	}
+
+	for _, from := range removed {
+		matched := false
+		for i, to := range added {
+			if to != nil && firewallRuleMatchKey(from) == firewallRuleMatchKey(to) {
+				diff.Changed = append(diff.Changed, &FirewallRuleChange{From: from, To: to})
+				added[i] = nil
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			diff.Removed = append(diff.Removed, from)
+		}
+	}
+	for _, rule := range added {
+		if rule != nil {
+			diff.Added = append(diff.Added, rule)
+		}
+	}
+
+	for i := range currentCommon {
+		if firewallRuleKey(currentCommon[i]) != firewallRuleKey(desiredCommon[i]) {
+			diff.Reordered = true
+			break
+		}
+	}
+	return diff
+}
+
+func DiffNATRules(current, desired []*NATRule) *NATRuleDiff {
+	diff := &NATRuleDiff{}
+
+	currentLeft := make(map[string]int)
+	for _, rule := range current {
+		currentLeft[natRuleKey(rule)]++
+	}
+	desiredLeft := make(map[string]int)
+	for _, rule := range desired {
+		desiredLeft[natRuleKey(rule)]++
+	}
+
+	var removed, added []*NATRule
+	for _, rule := range current {
+		key := natRuleKey(rule)
+		if desiredLeft[key] > 0 {
+			desiredLeft[key]--
+		} else {
+			removed = append(removed, rule)
+		}
+	}
+	for _, rule := range desired {
+		key := natRuleKey(rule)
+		if currentLeft[key] > 0 {
+			currentLeft[key]--
+		} else {
+			added = append(added, rule)
+		}
+	}
+
+	for _, from := range removed {
+		matched := false
+		for i, to := range added {
+			if to != nil && natRuleMatchKey(from) == natRuleMatchKey(to) {
+				diff.Changed = append(diff.Changed, &NATRuleChange{From: from, To: to})
+				added[i] = nil
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			diff.Removed = append(diff.Removed, from)
+		}
+	}
+	for _, rule := range added {
+		if rule != nil {
+			diff.Added = append(diff.Added, rule)
+		}
+	}
+	return diff
+}
+
+func (d *FirewallRuleDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && !d.Reordered
+}
+
+func (d *FirewallRuleDiff) String() string {
+	if d.Empty() {
+		return "No firewall changes.\n"
+	}
+	var b strings.Builder
+	for _, rule := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", rule)
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(&b, "~ %s\n  => %s\n", change.From, change.To)
+	}
+	for _, rule := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", rule)
+	}
+	if d.Reordered {
+		b.WriteString("! rule order changed\n")
+	}
+	return b.String()
+}
+
+func (d *FirewallRuleDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func (d *NATRuleDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d *NATRuleDiff) String() string {
+	if d.Empty() {
+		return "No NAT changes.\n"
+	}
+	var b strings.Builder
+	for _, rule := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", rule)
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(&b, "~ %s\n  => %s\n", change.From, change.To)
+	}
+	for _, rule := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", rule)
+	}
+	return b.String()
+}
+
+func (d *NATRuleDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func (g *GatewayEntity) DiffFirewall(desired []*FirewallRule) *FirewallRuleDiff {
+	return DiffFirewallRules(g.FirewallRules, desired)
+}
+
+func (g *GatewayEntity) DiffNAT(desired []*NATRule) *NATRuleDiff {
+	return DiffNATRules(g.NATRules, desired)
+}
+
+// PlanFirewall plans against the rules the entity was fetched with, see
+// SSClient.PlanFirewall for a plan against the latest stored rules.
+func (g *GatewayEntity) PlanFirewall(desired []*FirewallRule) (*FirewallPlan, error) {
+	if err := validateChangedFirewallRules(g.FirewallRules, desired); err != nil {
+		return nil, err
+	}
+	return &FirewallPlan{
+		GatewayID: g.ID,
+		Current:   g.FirewallRules,
+		Desired:   desired,
+		Diff:      g.DiffFirewall(desired),
+	}, nil
+}
+
+func (c *SSClient) PlanFirewall(gatewayID string, desired []*FirewallRule) (*FirewallPlan, error) {
+	current, err := c.GetFirewallRules(gatewayID)
+	if err != nil {
+		return nil, err
+	}
+	gateway := &GatewayEntity{ID: gatewayID, FirewallRules: current}
+	return gateway.PlanFirewall(desired)
+}
+
+func (c *SSClient) ApplyFirewall(plan *FirewallPlan) (*GatewayEntity, error) {
+	if plan.Diff.Empty() {
+		return c.GetGateway(plan.GatewayID)
+	}
+	return c.putFirewallRules(plan.GatewayID, plan.Current, plan.Desired)
+}
+
+func firewallRuleKey(rule *FirewallRule) string {
+	return string(rule.Action) + "|" + firewallRuleMatchKey(rule)
+}
+
+func firewallRuleMatchKey(rule *FirewallRule) string {
+	return strings.Join([]string{
+		string(rule.Direction),
+		string(normalizeProtocol(rule.Protocol)),
+		normalizeRuleAddress(rule.Source),
+		normalizePortRange(rule.SourcePort),
+		normalizeRuleAddress(rule.Destination),
+		normalizePortRange(rule.DestinationPort),
+	}, "|")
+}
+
+func natRuleKey(rule *NATRule) string {
+	return fmt.Sprintf(
+		"%s|%s:%d",
+		natRuleMatchKey(rule), normalizeRuleAddress(rule.Translated), rule.TranslatedPort,
+	)
+}
+
+func natRuleMatchKey(rule *NATRule) string {
+	return fmt.Sprintf(
+		"%s|%s|%s|%s:%d",
+		rule.RuleType, normalizeProtocol(rule.Protocol),
+		normalizeRuleAddress(rule.Source), normalizeRuleAddress(rule.Destination), rule.DestinationPort,
+	)
+}
+
+func normalizeProtocol(protocol ProtoType) ProtoType {
+	if protocol == "" {
+		return ProtocolIP
+	}
+	return protocol
+}
+
+func normalizeRuleAddress(address string) string {
+	if address == "" {
+		return ""
+	}
+	prefix, err := parseRuleAddress(address)
+	if err != nil {
+		return address
+	}
+	if prefix.Bits() == 0 {
+		return ""
+	}
+	if prefix.IsSingleIP() {
+		return prefix.Addr().String()
+	}
+	return prefix.String()
+}
+
+func normalizePortRange(port *PortRange) string {
+	if isAnyPort(port) {
+		return ""
+	}
+	return port.String()
+}
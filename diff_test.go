@@ -0,0 +1,116 @@
+package goss
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffFirewallRules(t *testing.T) {
+	ssh := &FirewallRule{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(22)}
+	web := &FirewallRule{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(443)}
+	denyAll := &FirewallRule{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolIP}
+	denySSH := &FirewallRule{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(22)}
+
+	tests := []struct {
+		name          string
+		current       []*FirewallRule
+		desired       []*FirewallRule
+		wantAdded     int
+		wantRemoved   int
+		wantChanged   int
+		wantReordered bool
+	}{
+		{name: "equal", current: []*FirewallRule{ssh, web}, desired: []*FirewallRule{ssh, web}},
+		{
+			name:    "semantically equal",
+			current: []*FirewallRule{{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Source: "0.0.0.0/0"}},
+			desired: []*FirewallRule{{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolIP}},
+		},
+		{name: "added", current: []*FirewallRule{ssh}, desired: []*FirewallRule{ssh, web}, wantAdded: 1},
+		{name: "removed", current: []*FirewallRule{ssh, web, denyAll}, desired: []*FirewallRule{ssh, web}, wantRemoved: 1},
+		{name: "changed action", current: []*FirewallRule{ssh, web}, desired: []*FirewallRule{denySSH, web}, wantChanged: 1},
+		{name: "reordered", current: []*FirewallRule{ssh, denyAll}, desired: []*FirewallRule{denyAll, ssh}, wantReordered: true},
+		{name: "duplicates", current: []*FirewallRule{ssh}, desired: []*FirewallRule{ssh, ssh}, wantAdded: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffFirewallRules(tt.current, tt.desired)
+			if len(diff.Added) != tt.wantAdded || len(diff.Removed) != tt.wantRemoved ||
+				len(diff.Changed) != tt.wantChanged || diff.Reordered != tt.wantReordered {
+				t.Errorf("DiffFirewallRules() =\n%s", diff)
+			}
+			empty := tt.wantAdded == 0 && tt.wantRemoved == 0 && tt.wantChanged == 0 && !tt.wantReordered
+			if diff.Empty() != empty {
+				t.Errorf("Empty() = %v, want %v", diff.Empty(), empty)
+			}
+		})
+	}
+}
+
+func TestDiffNATRules(t *testing.T) {
+	web := PortForward("1.2.3.4", 80, "10.0.0.5", 8080, ProtocolTCP)
+	webMoved := PortForward("1.2.3.4", 80, "10.0.0.6", 8080, ProtocolTCP)
+	snat := SNAT("10.0.0.0/24", "1.2.3.4")
+
+	tests := []struct {
+		name        string
+		current     []*NATRule
+		desired     []*NATRule
+		wantAdded   int
+		wantRemoved int
+		wantChanged int
+	}{
+		{name: "equal ignoring order", current: []*NATRule{web, snat}, desired: []*NATRule{snat, web}},
+		{name: "added", current: []*NATRule{snat}, desired: []*NATRule{snat, web}, wantAdded: 1},
+		{name: "removed", current: []*NATRule{snat, web}, desired: []*NATRule{web}, wantRemoved: 1},
+		{name: "changed target", current: []*NATRule{web}, desired: []*NATRule{webMoved}, wantChanged: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffNATRules(tt.current, tt.desired)
+			if len(diff.Added) != tt.wantAdded || len(diff.Removed) != tt.wantRemoved || len(diff.Changed) != tt.wantChanged {
+				t.Errorf("DiffNATRules() =\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFirewallRuleDiffJSON(t *testing.T) {
+	diff := DiffFirewallRules(nil, []*FirewallRule{
+		{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Ports(8000, 8080)},
+	})
+	data, err := diff.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &FirewallRuleDiff{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Added) != 1 || !decoded.Added[0].Equal(diff.Added[0]) {
+		t.Errorf("JSON() round trip = %s", data)
+	}
+}
+
+func TestGatewayPlanFirewall(t *testing.T) {
+	stored := &FirewallRule{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Source: "10.0.0.1/8"}
+	gateway := &GatewayEntity{ID: "gw", FirewallRules: []*FirewallRule{stored}}
+
+	plan, err := gateway.PlanFirewall([]*FirewallRule{
+		stored,
+		{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolIP},
+	})
+	if err != nil {
+		t.Fatalf("PlanFirewall() with an invalid stored rule error = %v", err)
+	}
+	if plan.GatewayID != "gw" || len(plan.Diff.Added) != 1 {
+		t.Errorf("PlanFirewall() diff =\n%s", plan.Diff)
+	}
+
+	_, err = gateway.PlanFirewall([]*FirewallRule{
+		{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Destination: "10.0.0.1/8"},
+	})
+	if err == nil {
+		t.Error("PlanFirewall() expected an error for an invalid new rule")
+	}
+}
@@ -124,11 +124,30 @@ func (c *SSClient) UpdateDomain(domainName string, cpu int, ram int) (*TaskIDWra
 }
 
 func (c *SSClient) DeleteDomain(domainName string) error {
-	url := fmt.Sprintf("%s/%s", domainBaseURL, domainName)
-	_, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	_, err := c.deleteDomain(domainName)
 	return err
 }
 
+func (c *SSClient) DeleteDomainAndWait(domainName string) error {
+	taskWrap, err := c.deleteDomain(domainName)
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetDomain(domainName)
+		return err
+	})
+}
+
+func (c *SSClient) deleteDomain(domainName string) (*TaskIDWrap, error) {
+	url := fmt.Sprintf("%s/%s", domainBaseURL, domainName)
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
 func (c *SSClient) GetDomainList() ([]*DomainResponse, error) {
 	resp, err := makeRequest(c.client, domainBaseURL, methodGet, nil, &domainListResponseWrap{})
 	if err != nil {
@@ -2,7 +2,10 @@ package goss
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -33,6 +36,57 @@ func NewWrongKeyFormatError(err error) *WrongKeyFormatError {
 	}
 }
 
+type ConcurrentModificationError struct {
+	BaseClientError
+	GatewayID string
+}
+
+func NewConcurrentModificationError(gatewayID string, resource string) *ConcurrentModificationError {
+	return &ConcurrentModificationError{
+		BaseClientError: BaseClientError{
+			Msg: "Concurrent modification",
+			Err: fmt.Errorf("%s of gateway '%s' changed since they were read", resource, gatewayID),
+		},
+		GatewayID: gatewayID,
+	}
+}
+
+type NATConflictError struct {
+	BaseClientError
+	Conflicts []*NATConflict
+}
+
+func NewNATConflictError(conflicts []*NATConflict) *NATConflictError {
+	reasons := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		reasons = append(reasons, fmt.Sprintf("rules #%d and #%d: %s", conflict.First, conflict.Second, conflict.Reason))
+	}
+	return &NATConflictError{
+		BaseClientError: BaseClientError{
+			Msg: "Conflicting NAT rules",
+			Err: errors.New(strings.Join(reasons, "; ")),
+		},
+		Conflicts: conflicts,
+	}
+}
+
+type ImageUploadError struct {
+	BaseClientError
+	SessionID string
+	Offset    int64
+}
+
+func NewImageUploadError(sessionID string, offset int64, err error) *ImageUploadError {
+	return &ImageUploadError{
+		BaseClientError: BaseClientError{
+			Msg: fmt.Sprintf("Image upload '%s' interrupted at %d bytes", sessionID, offset),
+			Err: err,
+		},
+		SessionID: sessionID,
+		Offset:    offset,
+	}
+}
+
 type ErrorBodyResponse struct {
 	Errors []*struct {
 		Code    int    `json:"code,omitempty"`
@@ -85,3 +139,11 @@ func (e *RequestError) Error() string {
 	errResp := e.Err.Error()
 	return fmt.Sprintf("%s: %s", e.Msg, errResp)
 }
+
+func IsNotFoundError(err error) bool {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.Status == http.StatusNotFound
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package goss
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type ExposedService struct {
+	GatewayID    string
+	ServerID     string
+	PublicIP     string
+	NATRule      *NATRule
+	FirewallRule *FirewallRule
+	DomainName   string
+	Record       *DomainRecordResponse
+}
+
+func (c *SSClient) ExposeService(
+	gatewayID string,
+	serverID string,
+	privatePort int,
+	publicPort int,
+	protocol ProtoType,
+	dnsName string,
+) (*ExposedService, error) {
+	gateway, err := c.GetGateway(gatewayID)
+	if err != nil {
+		return nil, err
+	}
+	publicIP, err := gatewayPublicIP(gateway)
+	if err != nil {
+		return nil, err
+	}
+	nic, err := c.findGatewayNIC(gateway, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	firewallRule, err := Allow().In().Protocol(protocol).To(publicIP).ToPort(publicPort).Build()
+	if err != nil {
+		return nil, err
+	}
+	service := &ExposedService{
+		GatewayID: gatewayID,
+		ServerID:  serverID,
+		PublicIP:  publicIP,
+	}
+
+	natRule := PortForward(publicIP, publicPort, nic.IPAddress, privatePort, protocol)
+	if _, err := c.AddNATRule(gatewayID, natRule); err != nil {
+		return nil, err
+	}
+	service.NATRule = natRule
+
+	index, err := firewallInsertIndex(gateway.FirewallRules, firewallRule)
+	if err != nil {
+		return nil, c.rollbackExpose(service, err)
+	}
+	gateway, err = c.InsertFirewallRuleAt(gatewayID, index, firewallRule)
+	if err != nil {
+		return nil, c.rollbackExpose(service, err)
+	}
+	service.FirewallRule = firewallRule
+	for _, issue := range LintFirewallRules(gateway.FirewallRules) {
+		if issue.Kind == FirewallLintShadowed && issue.Rule.Equal(firewallRule) {
+			return nil, c.rollbackExpose(service, fmt.Errorf(
+				"firewall rule %s on gateway '%s': %s", firewallRule, gatewayID, issue.Message,
+			))
+		}
+	}
+
+	if dnsName != "" {
+		domainName, err := c.findDomainFor(dnsName)
+		if err != nil {
+			return nil, c.rollbackExpose(service, err)
+		}
+		record, err := c.CreateRecordAndWait(domainName, DomainRecord{
+			Name: strings.TrimSuffix(dnsName, ".") + ".",
+			Type: ARecordType,
+			IP:   &publicIP,
+		})
+		if err != nil {
+			return nil, c.rollbackExpose(service, err)
+		}
+		service.DomainName = domainName
+		service.Record = record
+	}
+
+	return service, nil
+}
+
+func (c *SSClient) UnexposeService(service *ExposedService) error {
+	var firstErr error
+	if service.Record != nil {
+		if err := c.DeleteRecord(service.DomainName, strconv.Itoa(service.Record.ID)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if service.FirewallRule != nil {
+		if _, err := c.RemoveFirewallRule(service.GatewayID, service.FirewallRule); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if service.NATRule != nil {
+		if _, err := c.RemoveNATRule(service.GatewayID, service.NATRule); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (c *SSClient) rollbackExpose(service *ExposedService, cause error) error {
+	if err := c.UnexposeService(service); err != nil {
+		log.Default().Printf("[WARN] Failed to roll back exposed service on gateway '%s': %s", service.GatewayID, err)
+	}
+	return cause
+}
+
+func (c *SSClient) findGatewayNIC(gateway *GatewayEntity, serverID string) (*NICEntity, error) {
+	nics, err := c.GetNICList(serverID)
+	if err != nil {
+		return nil, err
+	}
+	for _, nic := range nics {
+		if nic.NetworkType != IsolatedNetwork {
+			continue
+		}
+		for _, networkID := range gateway.NetworkIDs {
+			if nic.NetworkID == networkID {
+				return nic, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("server '%s' has no NIC in networks of gateway '%s'", serverID, gateway.ID)
+}
+
+func (c *SSClient) findDomainFor(dnsName string) (string, error) {
+	domains, err := c.GetDomainList()
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSuffix(dnsName, ".")
+	best, bestLen := "", 0
+	for _, domain := range domains {
+		domainName := strings.TrimSuffix(domain.Name, ".")
+		if (name == domainName || strings.HasSuffix(name, "."+domainName)) && len(domainName) > bestLen {
+			best, bestLen = domain.Name, len(domainName)
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no domain found for '%s'", dnsName)
+	}
+	return best, nil
+}
+
+func gatewayPublicIP(gateway *GatewayEntity) (string, error) {
+	for _, nic := range gateway.NICS {
+		if nic.NetworkType == PublicSharedNetwork && nic.IPAddress != "" {
+			return nic.IPAddress, nil
+		}
+	}
+	return "", fmt.Errorf("gateway '%s' has no public IP address", gateway.ID)
+}
@@ -0,0 +1,406 @@
+package goss
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+const (
+	minPort = 1
+	maxPort = 65535
+)
+
+type (
+	// PortRange is sent as a plain number for a single port
+	// and as a "from-to" string for a range.
+	PortRange struct {
+		From int
+		To   int
+	}
+
+	FirewallRuleBuilder struct {
+		rule FirewallRule
+		err  error
+	}
+)
+
+func Port(port int) *PortRange {
+	return &PortRange{From: port, To: port}
+}
+
+func Ports(from, to int) *PortRange {
+	return &PortRange{From: from, To: to}
+}
+
+func ParsePortRange(s string) (*PortRange, error) {
+	s = strings.TrimSpace(s)
+	separator := strings.IndexAny(s, "-:")
+	if separator < 0 {
+		port, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port '%s'", s)
+		}
+		return Port(port), nil
+	}
+	from, err := strconv.Atoi(s[:separator])
+	if err != nil {
+		return nil, fmt.Errorf("invalid port range '%s'", s)
+	}
+	to, err := strconv.Atoi(s[separator+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid port range '%s'", s)
+	}
+	return Ports(from, to), nil
+}
+
+func (p *PortRange) Validate() error {
+	if p.From < minPort || p.To > maxPort || p.From > p.To {
+		return fmt.Errorf("invalid port range %s", p)
+	}
+	return nil
+}
+
+func (p *PortRange) Contains(port int) bool {
+	return port >= p.From && port <= p.To
+}
+
+func (p *PortRange) String() string {
+	if p.From == p.To {
+		return strconv.Itoa(p.From)
+	}
+	return fmt.Sprintf("%d-%d", p.From, p.To)
+}
+
+func (p PortRange) MarshalJSON() ([]byte, error) {
+	if p.From == p.To {
+		return json.Marshal(p.From)
+	}
+	return json.Marshal(p.String())
+}
+
+func (p *PortRange) UnmarshalJSON(data []byte) error {
+	var port int
+	if err := json.Unmarshal(data, &port); err == nil {
+		*p = PortRange{From: port, To: port}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParsePortRange(s)
+	if err != nil {
+		return err
+	}
+	*p = *parsed
+	return nil
+}
+
+func (r *FirewallRule) Validate() error {
+	switch r.Action {
+	case FirewallActionAllow, FirewallActionDeny:
+	default:
+		return fmt.Errorf("invalid firewall action '%s'", r.Action)
+	}
+	switch r.Direction {
+	case FirewallDirectionIn, FirewallDirectionOut:
+	default:
+		return fmt.Errorf("invalid firewall direction '%s'", r.Direction)
+	}
+	switch r.Protocol {
+	case "", ProtocolIP, ProtocolICMP:
+		if !isAnyPort(r.SourcePort) || !isAnyPort(r.DestinationPort) {
+			return fmt.Errorf("ports can't be used with protocol '%s'", r.Protocol)
+		}
+	case ProtocolTCP, ProtocolUDP:
+	default:
+		return fmt.Errorf("invalid firewall protocol '%s'", r.Protocol)
+	}
+	for _, port := range []*PortRange{r.SourcePort, r.DestinationPort} {
+		if isAnyPort(port) {
+			continue
+		}
+		if err := port.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, address := range []string{r.Source, r.Destination} {
+		if address == "" {
+			continue
+		}
+		if _, err := parseRuleAddress(address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *FirewallRule) String() string {
+	return fmt.Sprintf(
+		"%s %s %s %s:%s -> %s:%s",
+		r.Action, r.Direction, normalizeProtocol(r.Protocol),
+		formatRuleAddress(r.Source), formatPortRange(r.SourcePort),
+		formatRuleAddress(r.Destination), formatPortRange(r.DestinationPort),
+	)
+}
+
+func (r *FirewallRule) Equal(other *FirewallRule) bool {
+	return r.Action == other.Action &&
+		r.Direction == other.Direction &&
+		normalizeProtocol(r.Protocol) == normalizeProtocol(other.Protocol) &&
+		r.Source == other.Source &&
+		r.Destination == other.Destination &&
+		portRangesEqual(r.SourcePort, other.SourcePort) &&
+		portRangesEqual(r.DestinationPort, other.DestinationPort)
+}
+
+func (c *SSClient) AddFirewallRule(gatewayID string, rule *FirewallRule) (*GatewayEntity, error) {
+	return c.updateFirewallRules(gatewayID, func(rules []*FirewallRule) ([]*FirewallRule, error) {
+		return append(rules, rule), nil
+	})
+}
+
+func (c *SSClient) InsertFirewallRuleAt(gatewayID string, index int, rule *FirewallRule) (*GatewayEntity, error) {
+	return c.updateFirewallRules(gatewayID, func(rules []*FirewallRule) ([]*FirewallRule, error) {
+		if index < 0 || index > len(rules) {
+			return nil, fmt.Errorf("firewall rule index %d is out of range [0, %d]", index, len(rules))
+		}
+		rules = append(rules, nil)
+		copy(rules[index+1:], rules[index:])
+		rules[index] = rule
+		return rules, nil
+	})
+}
+
+func (c *SSClient) RemoveFirewallRule(gatewayID string, rule *FirewallRule) (*GatewayEntity, error) {
+	return c.updateFirewallRules(gatewayID, func(rules []*FirewallRule) ([]*FirewallRule, error) {
+		for i, existing := range rules {
+			if existing.Equal(rule) {
+				return append(rules[:i], rules[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("firewall rule %s wasn't found on gateway '%s'", rule, gatewayID)
+	})
+}
+
+func (c *SSClient) MoveFirewallRule(gatewayID string, from int, to int) (*GatewayEntity, error) {
+	return c.updateFirewallRules(gatewayID, func(rules []*FirewallRule) ([]*FirewallRule, error) {
+		if from < 0 || from >= len(rules) || to < 0 || to >= len(rules) {
+			return nil, fmt.Errorf("can't move firewall rule %d to %d, gateway has %d rules", from, to, len(rules))
+		}
+		rule := rules[from]
+		rules = append(rules[:from], rules[from+1:]...)
+		rules = append(rules[:to], append([]*FirewallRule{rule}, rules[to:]...)...)
+		return rules, nil
+	})
+}
+
+func (c *SSClient) updateFirewallRules(
+	gatewayID string,
+	mutate func([]*FirewallRule) ([]*FirewallRule, error),
+) (*GatewayEntity, error) {
+	current, err := c.GetFirewallRules(gatewayID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := mutate(append([]*FirewallRule(nil), current...))
+	if err != nil {
+		return nil, err
+	}
+	return c.putFirewallRules(gatewayID, current, updated)
+}
+
+func (c *SSClient) putFirewallRules(gatewayID string, expected, updated []*FirewallRule) (*GatewayEntity, error) {
+	taskWrap, err := c.editFirewallRules(gatewayID, updated, func(current []*FirewallRule) error {
+		if !firewallRulesEqual(expected, current) {
+			return NewConcurrentModificationError(gatewayID, "firewall rules")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.waitGateway(taskWrap.ID)
+}
+
+// Rules already stored on the gateway are sent back as is, even if they
+// wouldn't pass validation, so that unrelated edits aren't blocked.
+func validateChangedFirewallRules(current, rules []*FirewallRule) error {
+	for i, rule := range rules {
+		unchanged := false
+		for _, currentRule := range current {
+			if rule.Equal(currentRule) {
+				unchanged = true
+				break
+			}
+		}
+		if unchanged {
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("firewall rule #%d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func Allow() *FirewallRuleBuilder {
+	return &FirewallRuleBuilder{rule: FirewallRule{Action: FirewallActionAllow, Protocol: ProtocolIP}}
+}
+
+func Deny() *FirewallRuleBuilder {
+	return &FirewallRuleBuilder{rule: FirewallRule{Action: FirewallActionDeny, Protocol: ProtocolIP}}
+}
+
+func (b *FirewallRuleBuilder) In() *FirewallRuleBuilder {
+	b.rule.Direction = FirewallDirectionIn
+	return b
+}
+
+func (b *FirewallRuleBuilder) Out() *FirewallRuleBuilder {
+	b.rule.Direction = FirewallDirectionOut
+	return b
+}
+
+func (b *FirewallRuleBuilder) Protocol(protocol ProtoType) *FirewallRuleBuilder {
+	b.rule.Protocol = protocol
+	return b
+}
+
+func (b *FirewallRuleBuilder) TCP() *FirewallRuleBuilder {
+	return b.Protocol(ProtocolTCP)
+}
+
+func (b *FirewallRuleBuilder) UDP() *FirewallRuleBuilder {
+	return b.Protocol(ProtocolUDP)
+}
+
+func (b *FirewallRuleBuilder) ICMP() *FirewallRuleBuilder {
+	return b.Protocol(ProtocolICMP)
+}
+
+func (b *FirewallRuleBuilder) AnyProtocol() *FirewallRuleBuilder {
+	return b.Protocol(ProtocolIP)
+}
+
+func (b *FirewallRuleBuilder) From(address string) *FirewallRuleBuilder {
+	b.rule.Source = b.address(address)
+	return b
+}
+
+func (b *FirewallRuleBuilder) To(address string) *FirewallRuleBuilder {
+	b.rule.Destination = b.address(address)
+	return b
+}
+
+func (b *FirewallRuleBuilder) FromPort(port int) *FirewallRuleBuilder {
+	b.rule.SourcePort = Port(port)
+	return b
+}
+
+func (b *FirewallRuleBuilder) FromPorts(from, to int) *FirewallRuleBuilder {
+	b.rule.SourcePort = Ports(from, to)
+	return b
+}
+
+func (b *FirewallRuleBuilder) ToPort(port int) *FirewallRuleBuilder {
+	b.rule.DestinationPort = Port(port)
+	return b
+}
+
+func (b *FirewallRuleBuilder) ToPorts(from, to int) *FirewallRuleBuilder {
+	b.rule.DestinationPort = Ports(from, to)
+	return b
+}
+
+func (b *FirewallRuleBuilder) Build() (*FirewallRule, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.rule.Direction == "" {
+		return nil, errors.New("firewall rule direction isn't set, use In() or Out()")
+	}
+	rule := b.rule
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func BuildFirewallRules(builders ...*FirewallRuleBuilder) ([]*FirewallRule, error) {
+	rules := make([]*FirewallRule, 0, len(builders))
+	for i, builder := range builders {
+		rule, err := builder.Build()
+		if err != nil {
+			return nil, fmt.Errorf("firewall rule #%d: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (b *FirewallRuleBuilder) address(address string) string {
+	if _, err := parseRuleAddress(address); err != nil && b.err == nil {
+		b.err = err
+	}
+	return address
+}
+
+func firewallRulesEqual(a, b []*FirewallRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func portRangesEqual(a, b *PortRange) bool {
+	if isAnyPort(a) || isAnyPort(b) {
+		return isAnyPort(a) && isAnyPort(b)
+	}
+	return *a == *b
+}
+
+func formatRuleAddress(address string) string {
+	if address == "" {
+		return "any"
+	}
+	return address
+}
+
+func formatPortRange(port *PortRange) string {
+	if isAnyPort(port) {
+		return "any"
+	}
+	return port.String()
+}
+
+func isAnyPort(port *PortRange) bool {
+	return port == nil || (port.From == 0 && port.To == 0)
+}
+
+func parseRuleAddress(address string) (netip.Prefix, error) {
+	if strings.Contains(address, "/") {
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR '%s'", address)
+		}
+		if prefix.Masked() != prefix {
+			return netip.Prefix{}, fmt.Errorf("CIDR '%s' has host bits set, expected '%s'", address, prefix.Masked())
+		}
+		return prefix, nil
+	}
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address '%s'", address)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
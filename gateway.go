@@ -8,12 +8,14 @@ const gatewayBaseURL = "gateways"
 
 type ProtoType string
 type NATRuleType string
+type FirewallAction string
+type FirewallDirection string
 
 const (
-	FirewallActionAllow  string = "Allow"
-	FirewallActionDeny   string = "Deny"
-	FirewallDirectionIn  string = "In"
-	FirewallDirectionOut string = "Out"
+	FirewallActionAllow  FirewallAction    = "Allow"
+	FirewallActionDeny   FirewallAction    = "Deny"
+	FirewallDirectionIn  FirewallDirection = "In"
+	FirewallDirectionOut FirewallDirection = "Out"
 
 	NATRuleTypeSNAT  NATRuleType = "SNAT"
 	NATRuleTypeDNAT  NATRuleType = "DNAT"
@@ -27,13 +29,13 @@ const (
 
 type (
 	FirewallRule struct {
-		Action          string    `json:"action,omitempty"`
-		Direction       string    `json:"direction,omitempty"`
-		Protocol        ProtoType `json:"protocol,omitempty"`
-		Source          string    `json:"source,omitempty"`
-		SourcePort      int       `json:"source_port,omitempty"`
-		Destination     string    `json:"destination,omitempty"`
-		DestinationPort int       `json:"destination_port,omitempty"`
+		Action          FirewallAction    `json:"action,omitempty"`
+		Direction       FirewallDirection `json:"direction,omitempty"`
+		Protocol        ProtoType         `json:"protocol,omitempty"`
+		Source          string            `json:"source,omitempty"`
+		SourcePort      *PortRange        `json:"source_port,omitempty"`
+		Destination     string            `json:"destination,omitempty"`
+		DestinationPort *PortRange        `json:"destination_port,omitempty"`
 	}
 
 	NATRule struct {
@@ -134,23 +136,69 @@ func (c *SSClient) CreateGatewayAndWait(
 
 func (c *SSClient) RenameGateway(gatewayID string, name string) error {
 
+	_, err := c.renameGateway(gatewayID, name)
+
+	return err
+}
+
+func (c *SSClient) RenameGatewayAndWait(gatewayID string, name string) (*GatewayEntity, error) {
+
+	taskWrap, err := c.renameGateway(gatewayID, name)
+
+	if err != nil {
+		return nil, err
+	}
+	if taskWrap.ID == "" {
+		return c.GetGateway(gatewayID)
+	}
+	return c.waitGateway(taskWrap.ID)
+}
+
+func (c *SSClient) renameGateway(gatewayID string, name string) (*TaskIDWrap, error) {
+
 	url := fmt.Sprintf("%s/%s", gatewayBaseURL, gatewayID)
 	payload := map[string]interface{}{
 		"name": name,
 	}
 
-	_, err := makeRequest(c.client, url, methodPut, payload, nil)
+	resp, err := makeRequest(c.client, url, methodPut, payload, &TaskIDWrap{})
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
 }
 
 func (c *SSClient) DeleteGateway(gatewayID string) error {
 
+	_, err := c.deleteGateway(gatewayID)
+
+	return err
+}
+
+func (c *SSClient) DeleteGatewayAndWait(gatewayID string) error {
+
+	taskWrap, err := c.deleteGateway(gatewayID)
+
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetGateway(gatewayID)
+		return err
+	})
+}
+
+func (c *SSClient) deleteGateway(gatewayID string) (*TaskIDWrap, error) {
+
 	url := fmt.Sprintf("%s/%s", gatewayBaseURL, gatewayID)
 
-	_, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
 }
 
 func (c *SSClient) EditGatewayBandwidth(gatewayID string, bandwidthMbps int) (*TaskIDWrap, error) {
@@ -168,6 +216,63 @@ func (c *SSClient) EditGatewayBandwidth(gatewayID string, bandwidthMbps int) (*T
 	return resp.(*TaskIDWrap), nil
 }
 
+func (c *SSClient) EditGatewayBandwidthAndWait(gatewayID string, bandwidthMbps int) (*GatewayEntity, error) {
+
+	taskWrap, err := c.EditGatewayBandwidth(gatewayID, bandwidthMbps)
+
+	if err != nil {
+		return nil, err
+	}
+	return c.waitGateway(taskWrap.ID)
+}
+
+func (c *SSClient) AttachNetworkToGateway(gatewayID string, networkID string) (*TaskIDWrap, error) {
+
+	url := fmt.Sprintf("%s/%s/networks", gatewayBaseURL, gatewayID)
+	payload := map[string]interface{}{
+		"network_id": networkID,
+	}
+
+	resp, err := makeRequest(c.client, url, methodPost, payload, &TaskIDWrap{})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+func (c *SSClient) AttachNetworkToGatewayAndWait(gatewayID string, networkID string) (*GatewayEntity, error) {
+
+	taskWrap, err := c.AttachNetworkToGateway(gatewayID, networkID)
+
+	if err != nil {
+		return nil, err
+	}
+	return c.waitGateway(taskWrap.ID)
+}
+
+func (c *SSClient) DetachNetworkFromGateway(gatewayID string, networkID string) (*TaskIDWrap, error) {
+
+	url := fmt.Sprintf("%s/%s/networks/%s", gatewayBaseURL, gatewayID, networkID)
+
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+func (c *SSClient) DetachNetworkFromGatewayAndWait(gatewayID string, networkID string) (*GatewayEntity, error) {
+
+	taskWrap, err := c.DetachNetworkFromGateway(gatewayID, networkID)
+
+	if err != nil {
+		return nil, err
+	}
+	return c.waitGateway(taskWrap.ID)
+}
+
 func (c *SSClient) GetFirewallRules(gatewayID string) ([]*FirewallRule, error) {
 
 	url := fmt.Sprintf("%s/%s/firewall", gatewayBaseURL, gatewayID)
@@ -182,6 +287,31 @@ func (c *SSClient) GetFirewallRules(gatewayID string) ([]*FirewallRule, error) {
 
 func (c *SSClient) EditFirewallRules(gatewayID string, firewallRules []*FirewallRule) (*TaskIDWrap, error) {
 
+	return c.editFirewallRules(gatewayID, firewallRules, nil)
+}
+
+// editFirewallRules fetches the stored rules right before the PUT. check,
+// if set, gets them to detect concurrent modifications.
+func (c *SSClient) editFirewallRules(
+	gatewayID string,
+	firewallRules []*FirewallRule,
+	check func(current []*FirewallRule) error,
+) (*TaskIDWrap, error) {
+
+	current, err := c.GetFirewallRules(gatewayID)
+
+	if err != nil {
+		return nil, err
+	}
+	if check != nil {
+		if err := check(current); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateChangedFirewallRules(current, firewallRules); err != nil {
+		return nil, err
+	}
+
 	url := fmt.Sprintf("%s/%s/firewall", gatewayBaseURL, gatewayID)
 	payload := map[string]interface{}{
 		"firewall_rules": firewallRules,
@@ -218,6 +348,28 @@ func (c *SSClient) GetNATRules(gatewayID string) ([]*NATRule, error) {
 }
 
 func (c *SSClient) EditNATRules(gatewayID string, NATRules []*NATRule) (*TaskIDWrap, error) {
+	return c.editNATRules(gatewayID, NATRules, nil)
+}
+
+func (c *SSClient) editNATRules(
+	gatewayID string,
+	NATRules []*NATRule,
+	check func(current []*NATRule) error,
+) (*TaskIDWrap, error) {
+
+	current, err := c.GetNATRules(gatewayID)
+
+	if err != nil {
+		return nil, err
+	}
+	if check != nil {
+		if err := check(current); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.validateChangedNATRules(gatewayID, current, NATRules); err != nil {
+		return nil, err
+	}
 
 	url := fmt.Sprintf("%s/%s/nat", gatewayBaseURL, gatewayID)
 	payload := map[string]interface{}{
@@ -0,0 +1,230 @@
+package goss
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type GCResourceKind string
+
+const (
+	GCServer            GCResourceKind = "server"
+	GCNetwork           GCResourceKind = "network"
+	GCKubernetesCluster GCResourceKind = "kubernetes_cluster"
+	GCDNSRecord         GCResourceKind = "dns_record"
+	GCDomain            GCResourceKind = "domain"
+)
+
+var createdLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+type (
+	// A resource matches when it carries Tag or its name matches NamePattern.
+	// When OlderThan is set, matching resources without a known creation time
+	// (DNS records and zones among them) are reported as skipped. Whole zones
+	// are removed only with IncludeZones.
+	GCOptions struct {
+		Tag          string
+		NamePattern  *regexp.Regexp
+		OlderThan    time.Duration
+		IncludeZones bool
+		DryRun       bool
+	}
+
+	GCCandidate struct {
+		Kind       GCResourceKind
+		ID         string
+		Name       string
+		DomainName string
+		Created    time.Time
+		Reason     string
+	}
+
+	// Skipped holds matching resources that are kept, with the reason why.
+	GCPlan struct {
+		Candidates []*GCCandidate
+		Skipped    []*GCCandidate
+	}
+)
+
+func (c *SSClient) PlanGC(opts *GCOptions) (*GCPlan, error) {
+	if opts.Tag == "" && opts.NamePattern == nil {
+		return nil, errors.New("gc requires a tag or a name pattern")
+	}
+	plan := &GCPlan{}
+	now := time.Now()
+
+	clusters, err := c.GetKubernetesClusterList()
+	if err != nil {
+		return nil, err
+	}
+	clusterNodes := make(map[string]bool)
+	for _, cluster := range clusters {
+		plan.add(opts, now, &GCCandidate{Kind: GCKubernetesCluster, ID: cluster.ID, Name: cluster.Name}, cluster.Tags, cluster.Created)
+		for _, group := range cluster.NodeGroups {
+			for _, node := range group.Nodes {
+				clusterNodes[node] = true
+			}
+		}
+	}
+
+	servers, err := c.GetServerList()
+	if err != nil {
+		return nil, err
+	}
+	for _, server := range servers {
+		// Kubernetes nodes are managed by their cluster.
+		if clusterNodes[server.ID] {
+			continue
+		}
+		plan.add(opts, now, &GCCandidate{Kind: GCServer, ID: server.ID, Name: server.Name}, server.Tags, server.Created)
+	}
+
+	networks, err := c.GetNetworkList()
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range networks {
+		plan.add(opts, now, &GCCandidate{Kind: GCNetwork, ID: network.ID, Name: network.Name}, network.Tags, network.Created)
+	}
+
+	domains, err := c.GetDomainList()
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		zone := &GCCandidate{Kind: GCDomain, ID: domain.Name, Name: domain.Name}
+		if opts.IncludeZones && plan.add(opts, now, zone, nil, "") {
+			continue
+		}
+		records, err := c.GetRecordList(domain.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			plan.add(opts, now, &GCCandidate{
+				Kind:       GCDNSRecord,
+				ID:         strconv.Itoa(record.ID),
+				Name:       record.Name,
+				DomainName: domain.Name,
+			}, nil, "")
+		}
+	}
+
+	return plan, nil
+}
+
+func (c *SSClient) RunGC(opts *GCOptions) (*GCPlan, error) {
+	plan, err := c.PlanGC(opts)
+	if err != nil {
+		return nil, err
+	}
+	log.Default().Printf("[INFO] %s", plan)
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	failed := 0
+	for _, candidate := range plan.Candidates {
+		// A resource may already be gone together with its owner, e.g. a cluster.
+		if err := c.deleteGCCandidate(candidate); err != nil && !IsNotFoundError(err) {
+			log.Default().Printf("[WARN] Failed to remove %s '%s': %s", candidate.Kind, candidate.ID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return plan, fmt.Errorf("%d of %d resources weren't removed", failed, len(plan.Candidates))
+	}
+	return plan, nil
+}
+
+func (c *SSClient) deleteGCCandidate(candidate *GCCandidate) error {
+	switch candidate.Kind {
+	case GCKubernetesCluster:
+		return c.DeleteKubernetesClusterAndWait(candidate.ID)
+	case GCServer:
+		return c.DeleteServerAndWait(candidate.ID)
+	case GCNetwork:
+		return c.DeleteNetworkAndWait(candidate.ID)
+	case GCDNSRecord:
+		return c.DeleteRecord(candidate.DomainName, candidate.ID)
+	case GCDomain:
+		return c.DeleteDomainAndWait(candidate.ID)
+	default:
+		return fmt.Errorf("unknown resource kind '%s'", candidate.Kind)
+	}
+}
+
+func (p *GCPlan) add(opts *GCOptions, now time.Time, candidate *GCCandidate, tags []string, created string) bool {
+	matched := opts.NamePattern != nil && opts.NamePattern.MatchString(candidate.Name)
+	if opts.Tag != "" {
+		for _, tag := range tags {
+			if tag == opts.Tag {
+				matched = true
+				break
+			}
+		}
+	}
+	if !matched {
+		return false
+	}
+
+	createdAt, err := parseCreated(created)
+	candidate.Created = createdAt
+	if opts.OlderThan > 0 {
+		if err != nil {
+			candidate.Reason = "creation time is unknown"
+			p.Skipped = append(p.Skipped, candidate)
+			return false
+		}
+		if now.Sub(createdAt) < opts.OlderThan {
+			return false
+		}
+	}
+
+	p.Candidates = append(p.Candidates, candidate)
+	return true
+}
+
+func (p *GCPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d resources to remove:\n", len(p.Candidates))
+	for _, candidate := range p.Candidates {
+		fmt.Fprintf(&b, "  - %s\n", candidate)
+	}
+	if len(p.Skipped) > 0 {
+		fmt.Fprintf(&b, "%d matching resources skipped:\n", len(p.Skipped))
+		for _, candidate := range p.Skipped {
+			fmt.Fprintf(&b, "  - %s: %s\n", candidate, candidate.Reason)
+		}
+	}
+	return b.String()
+}
+
+func (c *GCCandidate) String() string {
+	created := "unknown"
+	if !c.Created.IsZero() {
+		created = c.Created.Format(time.RFC3339)
+	}
+	if c.DomainName != "" {
+		return fmt.Sprintf("%s '%s' (%s, domain %s, created %s)", c.Kind, c.Name, c.ID, c.DomainName, created)
+	}
+	return fmt.Sprintf("%s '%s' (%s, created %s)", c.Kind, c.Name, c.ID, created)
+}
+
+func parseCreated(created string) (time.Time, error) {
+	for _, layout := range createdLayouts {
+		if t, err := time.Parse(layout, created); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown creation time format '%s'", created)
+}
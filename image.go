@@ -1,5 +1,14 @@
 package goss
 
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var versionNumberRegexp = regexp.MustCompile(`\d+`)
+
 type (
 	ImageResponse struct {
 		ID           string `json:"id,omitempty"`
@@ -10,11 +19,34 @@ type (
 		AllowSSHKeys bool   `json:"allow_ssh_keys,omitempty"`
 	}
 
+	// OSVersion accepts an exact or prefix version ("22.04", "22")
+	// optionally preceded by one of the >=, <=, >, <, = operators.
+	ImageQuery struct {
+		Type         string
+		OSVersion    string
+		Architecture string
+		LocationID   string
+		AllowSSHKeys *bool
+	}
+
+	imageResponseWrap struct {
+		Image *ImageResponse `json:"image,omitempty"`
+	}
+
 	imageListResponseWrap struct {
 		Images []*ImageResponse `json:"images,omitempty"`
 	}
 )
 
+func (c *SSClient) GetImage(imageID string) (*ImageResponse, error) {
+	url := fmt.Sprintf("%s/%s", getImageBaseURL(), imageID)
+	resp, err := makeRequest(c.client, url, methodGet, nil, &imageResponseWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*imageResponseWrap).Image, nil
+}
+
 func (c *SSClient) GetImageList() ([]*ImageResponse, error) {
 	url := getImageBaseURL()
 	resp, err := makeRequest(c.client, url, methodGet, nil, &imageListResponseWrap{})
@@ -24,6 +56,126 @@ func (c *SSClient) GetImageList() ([]*ImageResponse, error) {
 	return resp.(*imageListResponseWrap).Images, nil
 }
 
+func (c *SSClient) FindImages(query *ImageQuery) ([]*ImageResponse, error) {
+	images, err := c.GetImageList()
+	if err != nil {
+		return nil, err
+	}
+	return FilterImages(images, query)
+}
+
+func (c *SSClient) FindLatestImage(query *ImageQuery) (*ImageResponse, error) {
+	if query == nil {
+		query = &ImageQuery{}
+	}
+	images, err := c.FindImages(query)
+	if err != nil {
+		return nil, err
+	}
+	var latest *ImageResponse
+	for _, image := range images {
+		if latest == nil || CompareOSVersions(image.OSVersion, latest.OSVersion) > 0 {
+			latest = image
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no image matches query %+v", *query)
+	}
+	return latest, nil
+}
+
+// A nil query matches all images.
+func FilterImages(images []*ImageResponse, query *ImageQuery) ([]*ImageResponse, error) {
+	if query == nil {
+		query = &ImageQuery{}
+	}
+	operator, version := splitVersionConstraint(query.OSVersion)
+	if operator != "" && version == "" {
+		return nil, fmt.Errorf("invalid OS version constraint '%s'", query.OSVersion)
+	}
+
+	var result []*ImageResponse
+	for _, image := range images {
+		if query.Type != "" && !strings.EqualFold(image.Type, query.Type) {
+			continue
+		}
+		if query.Architecture != "" && !strings.EqualFold(image.Architecture, query.Architecture) {
+			continue
+		}
+		if query.LocationID != "" && image.LocationID != query.LocationID {
+			continue
+		}
+		if query.AllowSSHKeys != nil && image.AllowSSHKeys != *query.AllowSSHKeys {
+			continue
+		}
+		if version != "" && !matchVersion(image.OSVersion, operator, version) {
+			continue
+		}
+		result = append(result, image)
+	}
+	return result, nil
+}
+
+func CompareOSVersions(a, b string) int {
+	aParts := versionNumberRegexp.FindAllString(a, -1)
+	bParts := versionNumberRegexp.FindAllString(b, -1)
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, _ := strconv.Atoi(aParts[i])
+		bNum, _ := strconv.Atoi(bParts[i])
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(aParts) < len(bParts):
+		return -1
+	case len(aParts) > len(bParts):
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func matchVersion(imageVersion, operator, version string) bool {
+	switch operator {
+	case ">=":
+		return CompareOSVersions(imageVersion, version) >= 0
+	case "<=":
+		return CompareOSVersions(imageVersion, version) <= 0
+	case ">":
+		return CompareOSVersions(imageVersion, version) > 0
+	case "<":
+		return CompareOSVersions(imageVersion, version) < 0
+	default:
+		imageParts := versionNumberRegexp.FindAllString(imageVersion, -1)
+		versionParts := versionNumberRegexp.FindAllString(version, -1)
+		if len(versionParts) == 0 || len(imageParts) < len(versionParts) {
+			return strings.EqualFold(imageVersion, version)
+		}
+		for i, part := range versionParts {
+			imageNum, _ := strconv.Atoi(imageParts[i])
+			num, _ := strconv.Atoi(part)
+			if imageNum != num {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func splitVersionConstraint(constraint string) (string, string) {
+	constraint = strings.TrimSpace(constraint)
+	for _, operator := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(constraint, operator) {
+			return operator, strings.TrimSpace(strings.TrimPrefix(constraint, operator))
+		}
+	}
+	return "", constraint
+}
+
 func getImageBaseURL() string {
 	return "images"
 }
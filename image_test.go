@@ -0,0 +1,92 @@
+package goss
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareOSVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "22.04", b: "20.04", want: 1},
+		{a: "20.04", b: "22.04", want: -1},
+		{a: "22.04", b: "22.04", want: 0},
+		{a: "9", b: "10", want: -1},
+		{a: "2019", b: "2022", want: -1},
+		{a: "8.10", b: "8.9", want: 1},
+		{a: "22.04", b: "22", want: 1},
+		{a: "7", b: "7.9", want: -1},
+		{a: "11 bullseye", b: "12 bookworm", want: -1},
+	}
+	for _, tt := range tests {
+		if got := CompareOSVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareOSVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMatchVersion(t *testing.T) {
+	tests := []struct {
+		image      string
+		constraint string
+		want       bool
+	}{
+		{image: "22.04", constraint: "22.04", want: true},
+		{image: "22.04", constraint: "22", want: true},
+		{image: "22.04", constraint: "20", want: false},
+		{image: "22", constraint: "22.04", want: false},
+		{image: "22.04", constraint: "= 22.04", want: true},
+		{image: "22.04", constraint: ">=20.04", want: true},
+		{image: "18.04", constraint: ">=20.04", want: false},
+		{image: "20.04", constraint: ">20.04", want: false},
+		{image: "20.04", constraint: "<=20.04", want: true},
+		{image: "8.10", constraint: "<8.9", want: false},
+		{image: "2022", constraint: "2022", want: true},
+	}
+	for _, tt := range tests {
+		operator, version := splitVersionConstraint(tt.constraint)
+		if got := matchVersion(tt.image, operator, version); got != tt.want {
+			t.Errorf("matchVersion(%q, %q) = %v, want %v", tt.image, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestFilterImages(t *testing.T) {
+	noKeys := false
+	images := []*ImageResponse{
+		{ID: "ubuntu-20", Type: "Ubuntu", OSVersion: "20.04", Architecture: "x64", LocationID: "spb", AllowSSHKeys: true},
+		{ID: "ubuntu-22", Type: "Ubuntu", OSVersion: "22.04", Architecture: "x64", LocationID: "spb", AllowSSHKeys: true},
+		{ID: "ubuntu-22-msk", Type: "Ubuntu", OSVersion: "22.04", Architecture: "x64", LocationID: "msk", AllowSSHKeys: true},
+		{ID: "windows", Type: "Windows", OSVersion: "2019", Architecture: "x64", LocationID: "spb"},
+	}
+	tests := []struct {
+		name    string
+		query   *ImageQuery
+		want    []string
+		wantErr bool
+	}{
+		{name: "nil query", query: nil, want: []string{"ubuntu-20", "ubuntu-22", "ubuntu-22-msk", "windows"}},
+		{name: "type", query: &ImageQuery{Type: "ubuntu", LocationID: "spb"}, want: []string{"ubuntu-20", "ubuntu-22"}},
+		{name: "version", query: &ImageQuery{Type: "Ubuntu", OSVersion: ">=22"}, want: []string{"ubuntu-22", "ubuntu-22-msk"}},
+		{name: "ssh keys", query: &ImageQuery{AllowSSHKeys: &noKeys}, want: []string{"windows"}},
+		{name: "no match", query: &ImageQuery{Architecture: "arm64"}},
+		{name: "invalid constraint", query: &ImageQuery{OSVersion: ">="}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := FilterImages(images, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FilterImages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, image := range filtered {
+				got = append(got, image.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterImages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package goss
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+type IPAM struct {
+	Supernet netip.Prefix
+	networks []*NetworkEntity
+}
+
+func ParseNetworkPrefix(networkPrefix string, mask int) (netip.Prefix, error) {
+	addr, err := netip.ParseAddr(networkPrefix)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid network prefix '%s': %w", networkPrefix, err)
+	}
+	if !addr.Is4() {
+		return netip.Prefix{}, fmt.Errorf("network prefix '%s' isn't an IPv4 address", networkPrefix)
+	}
+	if mask < 1 || mask > 32 {
+		return netip.Prefix{}, fmt.Errorf("invalid network mask %d", mask)
+	}
+	prefix := netip.PrefixFrom(addr, mask)
+	if prefix.Masked() != prefix {
+		return netip.Prefix{}, fmt.Errorf(
+			"network prefix '%s' has host bits set for mask %d, expected '%s'",
+			networkPrefix, mask, prefix.Masked().Addr(),
+		)
+	}
+	return prefix, nil
+}
+
+func (n *NetworkEntity) Prefix() (netip.Prefix, error) {
+	return ParseNetworkPrefix(n.NetworkPrefix, n.Mask)
+}
+
+func NewIPAM(supernet string, networks []*NetworkEntity) (*IPAM, error) {
+	prefix, err := netip.ParsePrefix(supernet)
+	if err != nil {
+		return nil, err
+	}
+	if !prefix.Addr().Is4() {
+		return nil, fmt.Errorf("supernet '%s' isn't an IPv4 prefix", supernet)
+	}
+	return &IPAM{Supernet: prefix.Masked(), networks: networks}, nil
+}
+
+func (c *SSClient) LoadIPAM(supernet string) (*IPAM, error) {
+	networks, err := c.GetNetworkList()
+	if err != nil {
+		return nil, err
+	}
+	return NewIPAM(supernet, networks)
+}
+
+func (i *IPAM) Overlaps(locationID string, prefix netip.Prefix) []*NetworkEntity {
+	var overlapping []*NetworkEntity
+	for _, network := range i.networks {
+		if network.LocationID != locationID {
+			continue
+		}
+		networkPrefix, err := network.Prefix()
+		if err != nil {
+			continue
+		}
+		if networkPrefix.Overlaps(prefix) {
+			overlapping = append(overlapping, network)
+		}
+	}
+	return overlapping
+}
+
+func (i *IPAM) Validate(locationID string, networkPrefix string, mask int) error {
+	prefix, err := ParseNetworkPrefix(networkPrefix, mask)
+	if err != nil {
+		return err
+	}
+	overlapping := i.Overlaps(locationID, prefix)
+	if len(overlapping) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(overlapping))
+	for _, network := range overlapping {
+		names = append(names, fmt.Sprintf("'%s' (%s/%d)", network.Name, network.NetworkPrefix, network.Mask))
+	}
+	return fmt.Errorf(
+		"network %s overlaps with networks in location '%s': %s",
+		prefix, locationID, strings.Join(names, ", "),
+	)
+}
+
+func (i *IPAM) NextFreeSubnet(locationID string, mask int) (netip.Prefix, error) {
+	if mask < i.Supernet.Bits() || mask > 32 {
+		return netip.Prefix{}, fmt.Errorf("mask %d doesn't fit into supernet %s", mask, i.Supernet)
+	}
+	blockSize := uint64(1) << (32 - mask)
+	first := uint64(addrToUint32(i.Supernet.Addr()))
+	last := first + (uint64(1) << (32 - i.Supernet.Bits()))
+
+	for candidate := first; candidate+blockSize <= last; {
+		prefix := netip.PrefixFrom(uint32ToAddr(uint32(candidate)), mask)
+		overlapping := i.Overlaps(locationID, prefix)
+		if len(overlapping) == 0 {
+			return prefix, nil
+		}
+
+		next := candidate + blockSize
+		for _, network := range overlapping {
+			networkPrefix, _ := network.Prefix()
+			end := uint64(addrToUint32(networkPrefix.Addr())) + (uint64(1) << (32 - networkPrefix.Bits()))
+			if end > next {
+				next = end
+			}
+		}
+		candidate = (next + blockSize - 1) / blockSize * blockSize
+	}
+	return netip.Prefix{}, fmt.Errorf(
+		"no free /%d subnet left in %s for location '%s'", mask, i.Supernet, locationID,
+	)
+}
+
+func addrToUint32(addr netip.Addr) uint32 {
+	bytes := addr.As4()
+	return binary.BigEndian.Uint32(bytes[:])
+}
+
+func uint32ToAddr(value uint32) netip.Addr {
+	var bytes [4]byte
+	binary.BigEndian.PutUint32(bytes[:], value)
+	return netip.AddrFrom4(bytes)
+}
@@ -0,0 +1,105 @@
+package goss
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func testIPAM(t *testing.T, supernet string) *IPAM {
+	t.Helper()
+	ipam, err := NewIPAM(supernet, []*NetworkEntity{
+		{ID: "a", Name: "a", LocationID: "spb", NetworkPrefix: "10.0.0.0", Mask: 24},
+		{ID: "b", Name: "b", LocationID: "spb", NetworkPrefix: "10.0.1.0", Mask: 25},
+		{ID: "c", Name: "c", LocationID: "spb", NetworkPrefix: "10.0.4.0", Mask: 22},
+		{ID: "d", Name: "d", LocationID: "msk", NetworkPrefix: "10.0.0.0", Mask: 16},
+		{ID: "e", Name: "e", LocationID: "spb", NetworkPrefix: "bogus", Mask: 24},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ipam
+}
+
+func TestParseNetworkPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		mask    int
+		want    string
+		wantErr bool
+	}{
+		{prefix: "10.0.0.0", mask: 24, want: "10.0.0.0/24"},
+		{prefix: "192.168.0.0", mask: 16, want: "192.168.0.0/16"},
+		{prefix: "10.0.0.1", mask: 24, wantErr: true},
+		{prefix: "10.0.0.0", mask: 0, wantErr: true},
+		{prefix: "10.0.0.0", mask: 33, wantErr: true},
+		{prefix: "fd00::", mask: 64, wantErr: true},
+		{prefix: "nope", mask: 24, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNetworkPrefix(tt.prefix, tt.mask)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNetworkPrefix(%s, %d) error = %v, wantErr %v", tt.prefix, tt.mask, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.String() != tt.want {
+			t.Errorf("ParseNetworkPrefix(%s, %d) = %s, want %s", tt.prefix, tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestIPAMOverlaps(t *testing.T) {
+	ipam := testIPAM(t, "10.0.0.0/16")
+	tests := []struct {
+		location string
+		prefix   string
+		want     []string
+	}{
+		{location: "spb", prefix: "10.0.0.0/24", want: []string{"a"}},
+		{location: "spb", prefix: "10.0.0.0/23", want: []string{"a", "b"}},
+		{location: "spb", prefix: "10.0.1.128/25"},
+		{location: "spb", prefix: "10.0.5.0/24", want: []string{"c"}},
+		{location: "spb", prefix: "10.0.0.0/8", want: []string{"a", "b", "c"}},
+		{location: "msk", prefix: "10.0.200.0/24", want: []string{"d"}},
+		{location: "ams", prefix: "10.0.0.0/24"},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, network := range ipam.Overlaps(tt.location, netip.MustParsePrefix(tt.prefix)) {
+			got = append(got, network.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Overlaps(%s, %s) = %v, want %v", tt.location, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestIPAMNextFreeSubnet(t *testing.T) {
+	tests := []struct {
+		name     string
+		supernet string
+		location string
+		mask     int
+		want     string
+		wantErr  bool
+	}{
+		{name: "gap after used", supernet: "10.0.0.0/16", location: "spb", mask: 25, want: "10.0.1.128/25"},
+		{name: "aligned block", supernet: "10.0.0.0/16", location: "spb", mask: 24, want: "10.0.2.0/24"},
+		{name: "skip larger network", supernet: "10.0.0.0/16", location: "spb", mask: 23, want: "10.0.2.0/23"},
+		{name: "after /22", supernet: "10.0.0.0/16", location: "spb", mask: 22, want: "10.0.8.0/22"},
+		{name: "other location", supernet: "10.0.0.0/16", location: "ams", mask: 24, want: "10.0.0.0/24"},
+		{name: "exhausted", supernet: "10.0.0.0/16", location: "msk", mask: 24, wantErr: true},
+		{name: "mask too wide", supernet: "10.0.0.0/16", location: "spb", mask: 8, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testIPAM(t, tt.supernet).NextFreeSubnet(tt.location, tt.mask)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NextFreeSubnet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("NextFreeSubnet() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,505 @@
+package goss
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+var ufwColumnsRegexp = regexp.MustCompile(`\s{2,}`)
+
+type (
+	UnsupportedRule struct {
+		Line   int    `json:"line"`
+		Text   string `json:"text"`
+		Reason string `json:"reason"`
+	}
+
+	FirewallImport struct {
+		Rules       []*FirewallRule    `json:"rules"`
+		Unsupported []*UnsupportedRule `json:"unsupported,omitempty"`
+	}
+
+	ruleImportError struct {
+		reason string
+	}
+
+	iptablesRule struct {
+		rule             FirewallRule
+		sourcePorts      []*PortRange
+		destinationPorts []*PortRange
+	}
+)
+
+func (e *ruleImportError) Error() string {
+	return e.reason
+}
+
+func ParseIptablesSave(r io.Reader) (*FirewallImport, error) {
+	result := &FirewallImport{}
+	table := ""
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || line == "COMMIT":
+			continue
+		case strings.HasPrefix(line, "*"):
+			table = strings.TrimPrefix(line, "*")
+			continue
+		case strings.HasPrefix(line, ":"):
+			fields := strings.Fields(strings.TrimPrefix(line, ":"))
+			if table == "filter" && len(fields) > 1 && fields[1] != "ACCEPT" && fields[1] != "-" {
+				result.unsupported(lineNumber, line, fmt.Sprintf("default policy %s isn't imported", fields[1]))
+			}
+			continue
+		}
+		if table != "filter" {
+			result.unsupported(lineNumber, line, fmt.Sprintf("table '%s' isn't supported", table))
+			continue
+		}
+
+		args, err := splitShellWords(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		rules, err := parseIptablesRule(args)
+		if err != nil {
+			if importErr, ok := err.(*ruleImportError); ok {
+				result.unsupported(lineNumber, line, importErr.reason)
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		result.Rules = append(result.Rules, rules...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func ParseUFWStatus(r io.Reader) (*FirewallImport, error) {
+	result := &FirewallImport{}
+	inRules := false
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if !inRules {
+			inRules = strings.HasPrefix(line, "--")
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if comment := strings.Index(line, " # "); comment >= 0 {
+			line = strings.TrimSpace(line[:comment])
+		}
+		rules, err := parseUFWRule(line)
+		if err != nil {
+			if importErr, ok := err.(*ruleImportError); ok {
+				result.unsupported(lineNumber, line, importErr.reason)
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		result.Rules = append(result.Rules, rules...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func FormatIptablesSave(rules []*FirewallRule) string {
+	var b strings.Builder
+	b.WriteString("*filter\n")
+	b.WriteString(":INPUT ACCEPT [0:0]\n")
+	b.WriteString(":FORWARD ACCEPT [0:0]\n")
+	b.WriteString(":OUTPUT ACCEPT [0:0]\n")
+	for _, rule := range rules {
+		chain := "INPUT"
+		if rule.Direction == FirewallDirectionOut {
+			chain = "OUTPUT"
+		}
+		fmt.Fprintf(&b, "-A %s", chain)
+		if source := iptablesAddress(rule.Source); source != "" {
+			fmt.Fprintf(&b, " -s %s", source)
+		}
+		if destination := iptablesAddress(rule.Destination); destination != "" {
+			fmt.Fprintf(&b, " -d %s", destination)
+		}
+		protocol := strings.ToLower(string(normalizeProtocol(rule.Protocol)))
+		if protocol != "ip" {
+			fmt.Fprintf(&b, " -p %s -m %s", protocol, protocol)
+		}
+		if !isAnyPort(rule.SourcePort) {
+			fmt.Fprintf(&b, " --sport %s", iptablesPortRange(rule.SourcePort))
+		}
+		if !isAnyPort(rule.DestinationPort) {
+			fmt.Fprintf(&b, " --dport %s", iptablesPortRange(rule.DestinationPort))
+		}
+		target := "ACCEPT"
+		if rule.Action == FirewallActionDeny {
+			target = "DROP"
+		}
+		fmt.Fprintf(&b, " -j %s\n", target)
+	}
+	b.WriteString("COMMIT\n")
+	return b.String()
+}
+
+func (g *GatewayEntity) IptablesSave() string {
+	return FormatIptablesSave(g.FirewallRules)
+}
+
+func (i *FirewallImport) unsupported(line int, text string, reason string) {
+	i.Unsupported = append(i.Unsupported, &UnsupportedRule{Line: line, Text: text, Reason: reason})
+}
+
+func parseIptablesRule(args []string) ([]*FirewallRule, error) {
+	parsed := &iptablesRule{rule: FirewallRule{Protocol: ProtocolIP}}
+	value := func(i int) (string, error) {
+		if i+1 >= len(args) {
+			return "", fmt.Errorf("option '%s' requires a value", args[i])
+		}
+		if strings.HasPrefix(args[i+1], "-") {
+			return "", &ruleImportError{fmt.Sprintf("option '%s' isn't supported", args[i])}
+		}
+		if args[i+1] == "!" {
+			return "", &ruleImportError{fmt.Sprintf("negated option '%s' isn't supported", args[i])}
+		}
+		return args[i+1], nil
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		if args[i] == "!" {
+			return nil, &ruleImportError{"negated matches aren't supported"}
+		}
+		arg, err := value(i)
+		if err != nil {
+			return nil, err
+		}
+		switch args[i] {
+		case "-A", "--append":
+			switch arg {
+			case "INPUT":
+				parsed.rule.Direction = FirewallDirectionIn
+			case "OUTPUT":
+				parsed.rule.Direction = FirewallDirectionOut
+			default:
+				return nil, &ruleImportError{fmt.Sprintf("chain '%s' isn't supported", arg)}
+			}
+		case "-p", "--protocol":
+			protocol, err := parseImportProtocol(arg)
+			if err != nil {
+				return nil, err
+			}
+			parsed.rule.Protocol = protocol
+		case "-s", "--source":
+			if parsed.rule.Source, err = parseImportAddress(arg); err != nil {
+				return nil, err
+			}
+		case "-d", "--destination":
+			if parsed.rule.Destination, err = parseImportAddress(arg); err != nil {
+				return nil, err
+			}
+		case "--sport", "--source-port", "--sports", "--source-ports":
+			if parsed.sourcePorts, err = parseImportPorts(arg); err != nil {
+				return nil, err
+			}
+		case "--dport", "--destination-port", "--dports", "--destination-ports":
+			if parsed.destinationPorts, err = parseImportPorts(arg); err != nil {
+				return nil, err
+			}
+		case "-m", "--match":
+			switch arg {
+			case "tcp", "udp", "icmp", "multiport", "comment":
+			default:
+				return nil, &ruleImportError{fmt.Sprintf("match module '%s' isn't supported", arg)}
+			}
+		case "--comment", "--reject-with":
+		case "-j", "--jump":
+			switch arg {
+			case "ACCEPT":
+				parsed.rule.Action = FirewallActionAllow
+			case "DROP", "REJECT":
+				parsed.rule.Action = FirewallActionDeny
+			default:
+				return nil, &ruleImportError{fmt.Sprintf("target '%s' isn't supported", arg)}
+			}
+		default:
+			return nil, &ruleImportError{fmt.Sprintf("option '%s' isn't supported", args[i])}
+		}
+	}
+
+	if parsed.rule.Direction == "" {
+		return nil, &ruleImportError{"rule has no chain"}
+	}
+	if parsed.rule.Action == "" {
+		return nil, &ruleImportError{"rule has no target"}
+	}
+	return parsed.expand()
+}
+
+func parseUFWRule(line string) ([]*FirewallRule, error) {
+	columns := ufwColumnsRegexp.Split(line, -1)
+	if len(columns) != 3 {
+		return nil, &ruleImportError{"unexpected ufw status format"}
+	}
+	if strings.Contains(columns[0], "(v6)") || strings.Contains(columns[2], "(v6)") {
+		return nil, &ruleImportError{"IPv6 rules aren't supported"}
+	}
+
+	parsed := &iptablesRule{rule: FirewallRule{Direction: FirewallDirectionIn}}
+	action := strings.Fields(columns[1])
+	switch action[0] {
+	case "ALLOW":
+		parsed.rule.Action = FirewallActionAllow
+	case "DENY", "REJECT":
+		parsed.rule.Action = FirewallActionDeny
+	default:
+		return nil, &ruleImportError{fmt.Sprintf("action '%s' isn't supported", action[0])}
+	}
+	if len(action) > 1 {
+		switch action[1] {
+		case "IN":
+		case "OUT":
+			parsed.rule.Direction = FirewallDirectionOut
+		default:
+			return nil, &ruleImportError{fmt.Sprintf("direction '%s' isn't supported", action[1])}
+		}
+	}
+
+	var (
+		toProtocol, fromProtocol ProtoType
+		err                      error
+	)
+	parsed.rule.Destination, parsed.destinationPorts, toProtocol, err = parseUFWEndpoint(columns[0])
+	if err != nil {
+		return nil, err
+	}
+	parsed.rule.Source, parsed.sourcePorts, fromProtocol, err = parseUFWEndpoint(columns[2])
+	if err != nil {
+		return nil, err
+	}
+
+	protocol := toProtocol
+	if protocol == "" {
+		protocol = fromProtocol
+	}
+	if protocol != "" {
+		parsed.rule.Protocol = protocol
+		return parsed.expand()
+	}
+	if len(parsed.sourcePorts) == 0 && len(parsed.destinationPorts) == 0 {
+		parsed.rule.Protocol = ProtocolIP
+		return parsed.expand()
+	}
+
+	// A port without a protocol means both TCP and UDP in ufw.
+	var rules []*FirewallRule
+	for _, protocol := range []ProtoType{ProtocolTCP, ProtocolUDP} {
+		parsed.rule.Protocol = protocol
+		expanded, err := parsed.expand()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, expanded...)
+	}
+	return rules, nil
+}
+
+func parseUFWEndpoint(endpoint string) (string, []*PortRange, ProtoType, error) {
+	fields := strings.Fields(endpoint)
+	if len(fields) == 0 {
+		return "", nil, "", &ruleImportError{fmt.Sprintf("endpoint '%s' isn't supported", endpoint)}
+	}
+
+	for i, field := range fields {
+		if field == "on" && i+1 < len(fields) {
+			return "", nil, "", &ruleImportError{
+				fmt.Sprintf("interface-bound rule '%s' isn't supported", endpoint),
+			}
+		}
+	}
+
+	// An endpoint is an optional address followed by an optional port spec,
+	// anything else (e.g. "Nginx Full") is an application profile.
+	address := ""
+	if isUFWAddress(fields[0]) {
+		address, fields = fields[0], fields[1:]
+	}
+	if len(fields) > 1 || len(fields) == 1 && !strings.ContainsAny(fields[0], "0123456789") {
+		return "", nil, "", &ruleImportError{fmt.Sprintf("application profile '%s' isn't supported", endpoint)}
+	}
+	portSpec := ""
+	if len(fields) == 1 {
+		portSpec = fields[0]
+	}
+
+	if address == "Anywhere" {
+		address = ""
+	} else if address != "" {
+		var err error
+		if address, err = parseImportAddress(address); err != nil {
+			return "", nil, "", err
+		}
+	}
+	if portSpec == "" {
+		return address, nil, "", nil
+	}
+
+	var protocol ProtoType
+	if slash := strings.LastIndex(portSpec, "/"); slash >= 0 {
+		var err error
+		if protocol, err = parseImportProtocol(portSpec[slash+1:]); err != nil {
+			return "", nil, "", err
+		}
+		portSpec = portSpec[:slash]
+	}
+	ports, err := parseImportPorts(portSpec)
+	if err != nil {
+		return "", nil, "", err
+	}
+	return address, ports, protocol, nil
+}
+
+func isUFWAddress(field string) bool {
+	if field == "Anywhere" {
+		return true
+	}
+	if _, err := netip.ParseAddr(field); err == nil {
+		return true
+	}
+	_, err := netip.ParsePrefix(field)
+	return err == nil
+}
+
+func (r *iptablesRule) expand() ([]*FirewallRule, error) {
+	sourcePorts := r.sourcePorts
+	if len(sourcePorts) == 0 {
+		sourcePorts = []*PortRange{nil}
+	}
+	destinationPorts := r.destinationPorts
+	if len(destinationPorts) == 0 {
+		destinationPorts = []*PortRange{nil}
+	}
+
+	var rules []*FirewallRule
+	for _, sourcePort := range sourcePorts {
+		for _, destinationPort := range destinationPorts {
+			rule := r.rule
+			rule.SourcePort = sourcePort
+			rule.DestinationPort = destinationPort
+			if err := rule.Validate(); err != nil {
+				return nil, &ruleImportError{err.Error()}
+			}
+			rules = append(rules, &rule)
+		}
+	}
+	return rules, nil
+}
+
+func parseImportProtocol(protocol string) (ProtoType, error) {
+	switch strings.ToLower(protocol) {
+	case "tcp":
+		return ProtocolTCP, nil
+	case "udp":
+		return ProtocolUDP, nil
+	case "icmp":
+		return ProtocolICMP, nil
+	case "all", "ip":
+		return ProtocolIP, nil
+	default:
+		return "", &ruleImportError{fmt.Sprintf("protocol '%s' isn't supported", protocol)}
+	}
+}
+
+func parseImportAddress(address string) (string, error) {
+	if strings.Contains(address, ",") {
+		return "", &ruleImportError{fmt.Sprintf("address list '%s' isn't supported", address)}
+	}
+	prefix, err := parseRuleAddress(address)
+	if err != nil {
+		return "", &ruleImportError{err.Error()}
+	}
+	if !prefix.Addr().Is4() {
+		return "", &ruleImportError{"IPv6 addresses aren't supported"}
+	}
+	return normalizeRuleAddress(address), nil
+}
+
+func parseImportPorts(ports string) ([]*PortRange, error) {
+	var ranges []*PortRange
+	for _, port := range strings.Split(ports, ",") {
+		portRange, err := ParsePortRange(port)
+		if err != nil {
+			return nil, &ruleImportError{err.Error()}
+		}
+		ranges = append(ranges, portRange)
+	}
+	return ranges, nil
+}
+
+func iptablesAddress(address string) string {
+	if address == "" {
+		return ""
+	}
+	prefix, err := parseRuleAddress(address)
+	if err != nil {
+		return address
+	}
+	if prefix.Bits() == 0 {
+		return ""
+	}
+	return prefix.String()
+}
+
+func iptablesPortRange(port *PortRange) string {
+	if port.From == port.To {
+		return fmt.Sprintf("%d", port.From)
+	}
+	return fmt.Sprintf("%d:%d", port.From, port.To)
+}
+
+func splitShellWords(line string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+	)
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in '%s'", line)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
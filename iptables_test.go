@@ -0,0 +1,185 @@
+package goss
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIptablesSave(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantRules       []string
+		wantUnsupported []int
+	}{
+		{
+			name: "filter rules",
+			input: `# Generated by iptables-save
+*filter
+:INPUT ACCEPT [0:0]
+:FORWARD ACCEPT [0:0]
+:OUTPUT ACCEPT [0:0]
+-A INPUT -p tcp -m tcp --dport 22 -j ACCEPT
+-A INPUT -s 10.0.0.0/8 -p udp -m multiport --dports 53,5353 -j ACCEPT
+-A INPUT -s 192.168.1.10/32 -j DROP
+-A OUTPUT -d 8.8.8.8/32 -p icmp -j REJECT --reject-with icmp-port-unreachable
+-A INPUT -p tcp -m comment --comment "web traffic" --dport 8000:8080 -j ACCEPT
+COMMIT
+`,
+			wantRules: []string{
+				"Allow In TCP any:any -> any:22",
+				"Allow In UDP 10.0.0.0/8:any -> any:53",
+				"Allow In UDP 10.0.0.0/8:any -> any:5353",
+				"Deny In IP 192.168.1.10:any -> any:any",
+				"Deny Out ICMP any:any -> 8.8.8.8:any",
+				"Allow In TCP any:any -> any:8000-8080",
+			},
+		},
+		{
+			name: "unsupported lines",
+			input: `*nat
+:PREROUTING ACCEPT [0:0]
+-A PREROUTING -p tcp --dport 80 -j DNAT --to-destination 10.0.0.5:80
+COMMIT
+*filter
+:INPUT DROP [0:0]
+-A INPUT -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT
+-A INPUT -p tcp --syn --dport 22 -j ACCEPT
+-A INPUT ! -s 10.0.0.0/8 -j DROP
+-A FORWARD -j ACCEPT
+-A INPUT -j LOG
+-A INPUT -s 10.0.0.0/8,172.16.0.0/12 -j ACCEPT
+-A INPUT -p tcp --dport 443 -j ACCEPT
+COMMIT
+`,
+			wantRules: []string{
+				"Allow In TCP any:any -> any:443",
+			},
+			wantUnsupported: []int{3, 6, 7, 8, 9, 10, 11, 12},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseIptablesSave(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseIptablesSave() error = %v", err)
+			}
+			checkFirewallImport(t, result, tt.wantRules, tt.wantUnsupported)
+		})
+	}
+}
+
+func TestParseUFWStatus(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantRules       []string
+		wantUnsupported []int
+		wantReasons     []string
+	}{
+		{
+			name: "rules",
+			input: `Status: active
+
+To                         Action      From
+--                         ------      ----
+22/tcp                     ALLOW       Anywhere
+80,443/tcp                 ALLOW       Anywhere
+53                         ALLOW       10.0.0.0/8
+Anywhere                   DENY        192.168.1.10
+8.8.8.8 53/udp             ALLOW OUT   Anywhere                   # dns
+`,
+			wantRules: []string{
+				"Allow In TCP any:any -> any:22",
+				"Allow In TCP any:any -> any:80",
+				"Allow In TCP any:any -> any:443",
+				"Allow In TCP 10.0.0.0/8:any -> any:53",
+				"Allow In UDP 10.0.0.0/8:any -> any:53",
+				"Deny In IP 192.168.1.10:any -> any:any",
+				"Allow Out UDP any:any -> 8.8.8.8:53",
+			},
+		},
+		{
+			name: "unsupported lines",
+			input: `Status: active
+
+To                         Action      From
+--                         ------      ----
+Nginx Full                 ALLOW       Anywhere
+OpenSSH                    ALLOW       10.0.0.0/8
+10.0.0.5 Samba             ALLOW       Anywhere
+22/tcp                     LIMIT       Anywhere
+22/tcp (v6)                ALLOW       Anywhere (v6)
+Anywhere on eth0           ALLOW       10.0.0.0/8
+22/tcp on eth1             DENY        Anywhere
+3306/tcp                   ALLOW       10.0.0.0/8
+`,
+			wantRules: []string{
+				"Allow In TCP 10.0.0.0/8:any -> any:3306",
+			},
+			wantUnsupported: []int{5, 6, 7, 8, 9, 10, 11},
+			wantReasons: []string{
+				"application profile 'Nginx Full'",
+				"application profile 'OpenSSH'",
+				"application profile '10.0.0.5 Samba'",
+				"action 'LIMIT'",
+				"IPv6",
+				"interface-bound rule 'Anywhere on eth0'",
+				"interface-bound rule '22/tcp on eth1'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseUFWStatus(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseUFWStatus() error = %v", err)
+			}
+			checkFirewallImport(t, result, tt.wantRules, tt.wantUnsupported)
+			for i, reason := range tt.wantReasons {
+				if i < len(result.Unsupported) && !strings.Contains(result.Unsupported[i].Reason, reason) {
+					t.Errorf("line %d reason = %q, want %q", result.Unsupported[i].Line, result.Unsupported[i].Reason, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatIptablesSave(t *testing.T) {
+	rules, err := BuildFirewallRules(
+		Allow().In().TCP().ToPort(22),
+		Deny().Out().UDP().To("10.0.0.0/8").ToPorts(1000, 2000),
+	)
+	if err != nil {
+		t.Fatalf("BuildFirewallRules() error = %v", err)
+	}
+	result, err := ParseIptablesSave(strings.NewReader(FormatIptablesSave(rules)))
+	if err != nil {
+		t.Fatalf("ParseIptablesSave() error = %v", err)
+	}
+	if len(result.Unsupported) != 0 {
+		t.Fatalf("ParseIptablesSave() unsupported = %+v", result.Unsupported)
+	}
+	if !firewallRulesEqual(rules, result.Rules) {
+		t.Fatalf("round trip = %v, want %v", result.Rules, rules)
+	}
+}
+
+func checkFirewallImport(t *testing.T, result *FirewallImport, wantRules []string, wantUnsupported []int) {
+	t.Helper()
+	var rules []string
+	for _, rule := range result.Rules {
+		rules = append(rules, rule.String())
+	}
+	if !reflect.DeepEqual(rules, wantRules) {
+		t.Errorf("rules = %q, want %q", rules, wantRules)
+	}
+	var lines []int
+	for _, unsupported := range result.Unsupported {
+		lines = append(lines, unsupported.Line)
+	}
+	if !reflect.DeepEqual(lines, wantUnsupported) {
+		t.Errorf("unsupported lines = %v, want %v (%+v)", lines, wantUnsupported, result.Unsupported)
+	}
+}
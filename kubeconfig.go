@@ -0,0 +1,253 @@
+package goss
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	KubeconfigCluster struct {
+		Server                   string                 `yaml:"server"`
+		CertificateAuthorityData string                 `yaml:"certificate-authority-data,omitempty"`
+		Extra                    map[string]interface{} `yaml:",inline"`
+	}
+
+	KubeconfigUser struct {
+		ClientCertificateData string                 `yaml:"client-certificate-data,omitempty"`
+		ClientKeyData         string                 `yaml:"client-key-data,omitempty"`
+		Token                 string                 `yaml:"token,omitempty"`
+		Extra                 map[string]interface{} `yaml:",inline"`
+	}
+
+	KubeconfigContext struct {
+		Cluster   string                 `yaml:"cluster"`
+		User      string                 `yaml:"user"`
+		Namespace string                 `yaml:"namespace,omitempty"`
+		Extra     map[string]interface{} `yaml:",inline"`
+	}
+
+	KubeconfigNamedCluster struct {
+		Name    string             `yaml:"name"`
+		Cluster *KubeconfigCluster `yaml:"cluster"`
+	}
+
+	KubeconfigNamedUser struct {
+		Name string          `yaml:"name"`
+		User *KubeconfigUser `yaml:"user"`
+	}
+
+	KubeconfigNamedContext struct {
+		Name    string             `yaml:"name"`
+		Context *KubeconfigContext `yaml:"context"`
+	}
+
+	Kubeconfig struct {
+		APIVersion     string                    `yaml:"apiVersion"`
+		Kind           string                    `yaml:"kind"`
+		Clusters       []*KubeconfigNamedCluster `yaml:"clusters"`
+		Contexts       []*KubeconfigNamedContext `yaml:"contexts"`
+		Users          []*KubeconfigNamedUser    `yaml:"users"`
+		CurrentContext string                    `yaml:"current-context"`
+		Extra          map[string]interface{}    `yaml:",inline"`
+	}
+
+	KubernetesCredentials struct {
+		Raw                      string
+		Config                   *Kubeconfig
+		ServerURL                string
+		CertificateAuthorityData []byte
+		User                     *KubeconfigUser
+	}
+
+	kubeconfigResponseWrap struct {
+		Kubeconfig string `json:"kubeconfig,omitempty"`
+	}
+)
+
+func (c *SSClient) GetKubeconfig(kubernetesClusterID string) (*KubernetesCredentials, error) {
+	url := fmt.Sprintf("%s/%s/kubeconfig", kubernetesBaseURL, kubernetesClusterID)
+	resp, err := makeRequest(c.client, url, methodGet, nil, &kubeconfigResponseWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return ParseKubeconfig(resp.(*kubeconfigResponseWrap).Kubeconfig)
+}
+
+func ParseKubeconfig(raw string) (*KubernetesCredentials, error) {
+	config := &Kubeconfig{}
+	if err := yaml.Unmarshal([]byte(raw), config); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	cluster, user, err := config.current()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := &KubernetesCredentials{
+		Raw:       raw,
+		Config:    config,
+		ServerURL: cluster.Cluster.Server,
+		User:      user.User,
+	}
+	if cluster.Cluster.CertificateAuthorityData != "" {
+		credentials.CertificateAuthorityData, err = base64.StdEncoding.DecodeString(cluster.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate authority data: %w", err)
+		}
+	}
+	return credentials, nil
+}
+
+func (c *SSClient) SaveKubeconfig(kubernetesClusterID, path, contextName string, setCurrent bool) error {
+	credentials, err := c.GetKubeconfig(kubernetesClusterID)
+	if err != nil {
+		return err
+	}
+	return MergeKubeconfig(path, contextName, credentials.Config, setCurrent)
+}
+
+// MergeKubeconfig adds the current context of config to the kubeconfig file
+// at path, naming its cluster, user and context contextName and replacing
+// entries with the same name.
+func MergeKubeconfig(path string, contextName string, config *Kubeconfig, setCurrent bool) error {
+	cluster, user, err := config.current()
+	if err != nil {
+		return err
+	}
+
+	target := &Kubeconfig{APIVersion: "v1", Kind: "Config"}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := yaml.Unmarshal(data, target); err != nil {
+			return fmt.Errorf("invalid kubeconfig '%s': %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	target.Clusters = append(
+		removeKubeconfigCluster(target.Clusters, contextName),
+		&KubeconfigNamedCluster{Name: contextName, Cluster: cluster.Cluster},
+	)
+	target.Users = append(
+		removeKubeconfigUser(target.Users, contextName),
+		&KubeconfigNamedUser{Name: contextName, User: user.User},
+	)
+	target.Contexts = append(
+		removeKubeconfigContext(target.Contexts, contextName),
+		&KubeconfigNamedContext{
+			Name:    contextName,
+			Context: &KubeconfigContext{Cluster: contextName, User: contextName},
+		},
+	)
+	if setCurrent || target.CurrentContext == "" {
+		target.CurrentContext = contextName
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes(), 0o600)
+}
+
+// The data is written to a temporary file next to path and renamed over it,
+// so a failed write never leaves a truncated kubeconfig behind.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(perm); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func DefaultKubeconfigPath() (string, error) {
+	if paths := filepath.SplitList(os.Getenv("KUBECONFIG")); len(paths) > 0 && paths[0] != "" {
+		return paths[0], nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".kube", "config"), nil
+}
+
+func (k *Kubeconfig) current() (*KubeconfigNamedCluster, *KubeconfigNamedUser, error) {
+	if len(k.Clusters) == 0 || len(k.Users) == 0 {
+		return nil, nil, errors.New("kubeconfig has no clusters or users")
+	}
+	cluster, user := k.Clusters[0], k.Users[0]
+	for _, context := range k.Contexts {
+		if context.Name != k.CurrentContext || context.Context == nil {
+			continue
+		}
+		for _, named := range k.Clusters {
+			if named.Name == context.Context.Cluster {
+				cluster = named
+			}
+		}
+		for _, named := range k.Users {
+			if named.Name == context.Context.User {
+				user = named
+			}
+		}
+	}
+	if cluster.Cluster == nil || user.User == nil {
+		return nil, nil, errors.New("kubeconfig has empty cluster or user")
+	}
+	return cluster, user, nil
+}
+
+func removeKubeconfigCluster(clusters []*KubeconfigNamedCluster, name string) []*KubeconfigNamedCluster {
+	result := clusters[:0]
+	for _, cluster := range clusters {
+		if cluster.Name != name {
+			result = append(result, cluster)
+		}
+	}
+	return result
+}
+
+func removeKubeconfigUser(users []*KubeconfigNamedUser, name string) []*KubeconfigNamedUser {
+	result := users[:0]
+	for _, user := range users {
+		if user.Name != name {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
+func removeKubeconfigContext(contexts []*KubeconfigNamedContext, name string) []*KubeconfigNamedContext {
+	result := contexts[:0]
+	for _, context := range contexts {
+		if context.Name != name {
+			result = append(result, context)
+		}
+	}
+	return result
+}
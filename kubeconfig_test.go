@@ -0,0 +1,127 @@
+package goss
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testExistingKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: other
+  cluster:
+    server: https://other.example.com
+    insecure-skip-tls-verify: true
+contexts:
+- name: other
+  context:
+    cluster: other
+    user: other
+    extensions:
+    - name: note
+users:
+- name: other
+  user:
+    token: secret
+    exec:
+      command: other-login
+current-context: other
+preferences:
+  colors: true
+`
+
+func testClusterKubeconfig() *Kubeconfig {
+	return &Kubeconfig{
+		Clusters: []*KubeconfigNamedCluster{{Name: "default", Cluster: &KubeconfigCluster{Server: "https://new.example.com"}}},
+		Users:    []*KubeconfigNamedUser{{Name: "admin", User: &KubeconfigUser{Token: "token"}}},
+		Contexts: []*KubeconfigNamedContext{{Name: "default", Context: &KubeconfigContext{Cluster: "default", User: "admin"}}},
+	}
+}
+
+func TestMergeKubeconfigIntoExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testExistingKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MergeKubeconfig(path, "new", testClusterKubeconfig(), false); err != nil {
+		t.Fatalf("MergeKubeconfig() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := &Kubeconfig{}
+	if err := yaml.Unmarshal(data, merged); err != nil {
+		t.Fatal(err)
+	}
+
+	if merged.CurrentContext != "other" {
+		t.Errorf("current context = %q, want %q", merged.CurrentContext, "other")
+	}
+	var names []string
+	for _, context := range merged.Contexts {
+		names = append(names, context.Name)
+	}
+	if want := []string{"other", "new"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("contexts = %v, want %v", names, want)
+	}
+	if merged.Extra["preferences"] == nil {
+		t.Error("top level 'preferences' was dropped")
+	}
+	if merged.Clusters[0].Cluster.Extra["insecure-skip-tls-verify"] != true {
+		t.Errorf("cluster extra fields = %v", merged.Clusters[0].Cluster.Extra)
+	}
+	if merged.Users[0].User.Token != "secret" || merged.Users[0].User.Extra["exec"] == nil {
+		t.Errorf("user 'other' = %+v", merged.Users[0].User)
+	}
+	if merged.Contexts[0].Context.Extra["extensions"] == nil {
+		t.Errorf("context extra fields = %v", merged.Contexts[0].Context.Extra)
+	}
+	if merged.Clusters[1].Cluster.Server != "https://new.example.com" {
+		t.Errorf("cluster 'new' server = %q", merged.Clusters[1].Cluster.Server)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the kubeconfig", len(entries))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("kubeconfig mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestMergeKubeconfigReplacesContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testExistingKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MergeKubeconfig(path, "other", testClusterKubeconfig(), true); err != nil {
+		t.Fatalf("MergeKubeconfig() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials, err := ParseKubeconfig(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(credentials.Config.Contexts) != 1 || credentials.ServerURL != "https://new.example.com" {
+		t.Errorf("merged kubeconfig =\n%s", data)
+	}
+}
@@ -15,6 +15,7 @@ type (
 		HighAvailability bool                         `json:"high_availability,omitempty"`
 		NodeGroups       []*KubernetesNodeGroupEntity `json:"node_groups,omitempty"`
 		State            string                       `json:"state,omitempty"`
+		Created          string                       `json:"created,omitempty"`
 		Tags             []string                     `json:"tags,omitempty"`
 	}
 
@@ -110,11 +111,34 @@ func (c *SSClient) GetKubernetesNodeGroup(kubernetesClusterID string, nodeGroupI
 
 func (c *SSClient) DeleteKubernetesCluster(kubernetesClusterID string) error {
 
+	_, err := c.deleteKubernetesCluster(kubernetesClusterID)
+
+	return err
+}
+
+func (c *SSClient) DeleteKubernetesClusterAndWait(kubernetesClusterID string) error {
+
+	taskWrap, err := c.deleteKubernetesCluster(kubernetesClusterID)
+
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetKubernetesCluster(kubernetesClusterID)
+		return err
+	})
+}
+
+func (c *SSClient) deleteKubernetesCluster(kubernetesClusterID string) (*TaskIDWrap, error) {
+
 	url := fmt.Sprintf("%s/%s", kubernetesBaseURL, kubernetesClusterID)
 
-	_, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
 }
 
 func (c *SSClient) DeleteKubernetesNodeGroup(kubernetesClusterID string, nodeGroupID string) error {
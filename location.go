@@ -1,5 +1,13 @@
 package goss
 
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultLocationCacheTTL = time.Hour
+
 type (
 	LocationEntity struct {
 		ID                     string `json:"id,omitempty"`
@@ -20,8 +28,24 @@ type (
 	locationListResponseWrap struct {
 		Locations []*LocationEntity `json:"locations,omitempty"`
 	}
+
+	locationCatalog struct {
+		mu        sync.Mutex
+		ttl       time.Duration
+		fetched   time.Time
+		locations []*LocationEntity
+	}
 )
 
+func (c *SSClient) GetLocation(locationID string) (*LocationEntity, error) {
+	url := fmt.Sprintf("%s/%s", getLocationBaseURL(), locationID)
+	resp, err := makeRequest(c.client, url, methodGet, nil, &LocationEntityWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*LocationEntityWrap).Location, nil
+}
+
 func (c *SSClient) GetLocationList() ([]*LocationEntity, error) {
 	url := getLocationBaseURL()
 	resp, err := makeRequest(c.client, url, methodGet, nil, &locationListResponseWrap{})
@@ -31,6 +55,108 @@ func (c *SSClient) GetLocationList() ([]*LocationEntity, error) {
 	return resp.(*locationListResponseWrap).Locations, nil
 }
 
+func (c *SSClient) SetLocationCacheTTL(ttl time.Duration) {
+	c.locationCache.mu.Lock()
+	defer c.locationCache.mu.Unlock()
+	c.locationCache.ttl = ttl
+}
+
+func (c *SSClient) RefreshLocationCache() error {
+	c.locationCache.mu.Lock()
+	defer c.locationCache.mu.Unlock()
+	return c.refreshLocationCache()
+}
+
+func (c *SSClient) GetCachedLocationList() ([]*LocationEntity, error) {
+	c.locationCache.mu.Lock()
+	defer c.locationCache.mu.Unlock()
+
+	ttl := c.locationCache.ttl
+	if ttl <= 0 {
+		ttl = defaultLocationCacheTTL
+	}
+	if c.locationCache.locations == nil || time.Since(c.locationCache.fetched) > ttl {
+		if err := c.refreshLocationCache(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Callers get copies so they can't modify the shared cache.
+	locations := make([]*LocationEntity, 0, len(c.locationCache.locations))
+	for _, location := range c.locationCache.locations {
+		locations = append(locations, location.copy())
+	}
+	return locations, nil
+}
+
+func (l *LocationEntity) copy() *LocationEntity {
+	location := *l
+	location.CPUQuantityOptions = append([]int(nil), l.CPUQuantityOptions...)
+	location.RAMSizeOptions = append([]int(nil), l.RAMSizeOptions...)
+	return &location
+}
+
+func (c *SSClient) GetCachedLocation(locationID string) (*LocationEntity, error) {
+	locations, err := c.GetCachedLocationList()
+	if err != nil {
+		return nil, err
+	}
+	for _, location := range locations {
+		if location.ID == locationID {
+			return location, nil
+		}
+	}
+	return nil, fmt.Errorf("location '%s' wasn't found", locationID)
+}
+
+func (c *SSClient) refreshLocationCache() error {
+	locations, err := c.GetLocationList()
+	if err != nil {
+		return err
+	}
+	c.locationCache.locations = locations
+	c.locationCache.fetched = time.Now()
+	return nil
+}
+
+func (l *LocationEntity) SupportsCPU(cpu int) bool {
+	for _, option := range l.CPUQuantityOptions {
+		if option == cpu {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *LocationEntity) NearestRAM(ram int) int {
+	nearest := 0
+	for _, option := range l.RAMSizeOptions {
+		if nearest == 0 {
+			nearest = option
+			continue
+		}
+		distance, nearestDistance := abs(option-ram), abs(nearest-ram)
+		if distance < nearestDistance || (distance == nearestDistance && option > nearest) {
+			nearest = option
+		}
+	}
+	return nearest
+}
+
+func (l *LocationEntity) ValidBandwidth(bandwidth int) bool {
+	if bandwidth < l.BandwidthMin {
+		return false
+	}
+	return l.BandwidthMax == 0 || bandwidth <= l.BandwidthMax
+}
+
 func getLocationBaseURL() string {
 	return "locations"
 }
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
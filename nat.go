@@ -0,0 +1,351 @@
+package goss
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+type NATConflict struct {
+	First  int
+	Second int
+	Reason string
+}
+
+// The public IPs and attached network prefixes NAT rules on a gateway may use.
+type gatewayNATScope struct {
+	gatewayID       string
+	publicIPs       map[netip.Addr]bool
+	privatePrefixes []netip.Prefix
+}
+
+func PortForward(publicIP string, publicPort int, privateIP string, privatePort int, protocol ProtoType) *NATRule {
+	return &NATRule{
+		RuleType:        NATRuleTypeDNAT,
+		Protocol:        protocol,
+		Destination:     publicIP,
+		DestinationPort: publicPort,
+		Translated:      privateIP,
+		TranslatedPort:  privatePort,
+	}
+}
+
+func SNAT(source string, publicIP string) *NATRule {
+	return &NATRule{
+		RuleType:   NATRuleTypeSNAT,
+		Protocol:   ProtocolIP,
+		Source:     source,
+		Translated: publicIP,
+	}
+}
+
+func BINAT(publicIP string, privateIP string) *NATRule {
+	return &NATRule{
+		RuleType:    NATRuleTypeBINAT,
+		Protocol:    ProtocolIP,
+		Destination: publicIP,
+		Translated:  privateIP,
+	}
+}
+
+func (r *NATRule) String() string {
+	return fmt.Sprintf(
+		"%s %s %s -> %s:%d => %s:%d",
+		r.RuleType, r.Protocol, formatRuleAddress(r.Source),
+		formatRuleAddress(r.Destination), r.DestinationPort,
+		formatRuleAddress(r.Translated), r.TranslatedPort,
+	)
+}
+
+func (r *NATRule) Equal(other *NATRule) bool {
+	return natRuleKey(r) == natRuleKey(other)
+}
+
+func (c *SSClient) AddNATRule(gatewayID string, rule *NATRule) (*GatewayEntity, error) {
+	return c.updateNATRules(gatewayID, func(rules []*NATRule) ([]*NATRule, error) {
+		return append(rules, rule), nil
+	})
+}
+
+func (c *SSClient) RemoveNATRule(gatewayID string, rule *NATRule) (*GatewayEntity, error) {
+	return c.updateNATRules(gatewayID, func(rules []*NATRule) ([]*NATRule, error) {
+		for i, existing := range rules {
+			if existing.Equal(rule) {
+				return append(rules[:i], rules[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("NAT rule %s wasn't found on gateway '%s'", rule, gatewayID)
+	})
+}
+
+func (c *SSClient) ReplaceNATRule(gatewayID string, oldRule *NATRule, newRule *NATRule) (*GatewayEntity, error) {
+	return c.updateNATRules(gatewayID, func(rules []*NATRule) ([]*NATRule, error) {
+		for i, existing := range rules {
+			if existing.Equal(oldRule) {
+				rules[i] = newRule
+				return rules, nil
+			}
+		}
+		return nil, fmt.Errorf("NAT rule %s wasn't found on gateway '%s'", oldRule, gatewayID)
+	})
+}
+
+func (c *SSClient) updateNATRules(
+	gatewayID string,
+	mutate func([]*NATRule) ([]*NATRule, error),
+) (*GatewayEntity, error) {
+	current, err := c.GetNATRules(gatewayID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := mutate(append([]*NATRule(nil), current...))
+	if err != nil {
+		return nil, err
+	}
+
+	taskWrap, err := c.editNATRules(gatewayID, updated, func(latest []*NATRule) error {
+		if !natRulesEqual(current, latest) {
+			return NewConcurrentModificationError(gatewayID, "NAT rules")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.waitGateway(taskWrap.ID)
+}
+
+func (r *NATRule) Validate() error {
+	protocol := normalizeProtocol(r.Protocol)
+	switch protocol {
+	case ProtocolIP, ProtocolICMP:
+		if r.DestinationPort != 0 || r.TranslatedPort != 0 {
+			return fmt.Errorf("ports can't be used with protocol '%s'", r.Protocol)
+		}
+	case ProtocolTCP, ProtocolUDP:
+	default:
+		return fmt.Errorf("invalid NAT protocol '%s'", r.Protocol)
+	}
+	for _, port := range []int{r.DestinationPort, r.TranslatedPort} {
+		if port < 0 || port > maxPort {
+			return fmt.Errorf("invalid port %d", port)
+		}
+	}
+
+	switch r.RuleType {
+	case NATRuleTypeDNAT, NATRuleTypeBINAT:
+		if err := validateNATAddress("destination", r.Destination); err != nil {
+			return err
+		}
+		if r.RuleType == NATRuleTypeBINAT && (r.DestinationPort != 0 || r.TranslatedPort != 0) {
+			return fmt.Errorf("ports can't be used with %s rules", r.RuleType)
+		}
+	case NATRuleTypeSNAT:
+		if r.Source == "" {
+			return fmt.Errorf("SNAT rule requires a source")
+		}
+		if _, err := parseRuleAddress(r.Source); err != nil {
+			return err
+		}
+		if r.DestinationPort != 0 || r.TranslatedPort != 0 {
+			return fmt.Errorf("ports can't be used with %s rules", r.RuleType)
+		}
+	default:
+		return fmt.Errorf("invalid NAT rule type '%s'", r.RuleType)
+	}
+	return validateNATAddress("translated", r.Translated)
+}
+
+func ValidateGatewayNATRules(gateway *GatewayEntity, networks []*NetworkEntity, rules []*NATRule) error {
+	scope := newGatewayNATScope(gateway, networks)
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("NAT rule #%d: %w", i, err)
+		}
+		if err := scope.check(rule); err != nil {
+			return fmt.Errorf("NAT rule #%d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func newGatewayNATScope(gateway *GatewayEntity, networks []*NetworkEntity) *gatewayNATScope {
+	scope := &gatewayNATScope{gatewayID: gateway.ID, publicIPs: make(map[netip.Addr]bool)}
+	for _, nic := range gateway.NICS {
+		if addr, err := netip.ParseAddr(nic.IPAddress); err == nil && nic.NetworkType == PublicSharedNetwork {
+			scope.publicIPs[addr] = true
+		}
+	}
+	for _, network := range networks {
+		for _, networkID := range gateway.NetworkIDs {
+			if network.ID != networkID {
+				continue
+			}
+			if prefix, err := network.Prefix(); err == nil {
+				scope.privatePrefixes = append(scope.privatePrefixes, prefix)
+			}
+		}
+	}
+	return scope
+}
+
+func (s *gatewayNATScope) check(rule *NATRule) error {
+	publicIP, privateAddress := rule.Destination, rule.Translated
+	if rule.RuleType == NATRuleTypeSNAT {
+		publicIP, privateAddress = rule.Translated, rule.Source
+	}
+
+	addr, _ := netip.ParseAddr(publicIP)
+	if !s.publicIPs[addr] {
+		return fmt.Errorf("'%s' isn't a public IP of gateway '%s'", publicIP, s.gatewayID)
+	}
+	private, _ := parseRuleAddress(privateAddress)
+	if !prefixInAny(private, s.privatePrefixes) {
+		return fmt.Errorf("'%s' isn't in networks attached to gateway '%s'", privateAddress, s.gatewayID)
+	}
+	return nil
+}
+
+func (c *SSClient) ValidateNATRules(gatewayID string, rules []*NATRule) error {
+	gateway, err := c.GetGateway(gatewayID)
+	if err != nil {
+		return err
+	}
+	networks, err := c.GetNetworkList()
+	if err != nil {
+		return err
+	}
+	if err := ValidateGatewayNATRules(gateway, networks, rules); err != nil {
+		return err
+	}
+	if conflicts := FindNATConflicts(rules); len(conflicts) > 0 {
+		return NewNATConflictError(conflicts)
+	}
+	return nil
+}
+
+func FindNATConflicts(rules []*NATRule) []*NATConflict {
+	var conflicts []*NATConflict
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			if reason := natRulesConflict(rules[i], rules[j]); reason != "" {
+				conflicts = append(conflicts, &NATConflict{First: i, Second: j, Reason: reason})
+			}
+		}
+	}
+	return conflicts
+}
+
+// Rules already stored on the gateway are neither validated nor checked for
+// conflicts with each other, so removing or replacing a rule isn't blocked by
+// problems the gateway already has. The gateway addresses are only loaded
+// when there are new or changed rules.
+func (c *SSClient) validateChangedNATRules(gatewayID string, current, rules []*NATRule) error {
+	var scope *gatewayNATScope
+	changed := make([]bool, len(rules))
+	matched := make([]bool, len(current))
+	for i, rule := range rules {
+		changed[i] = true
+		for j, currentRule := range current {
+			if !matched[j] && rule.Equal(currentRule) {
+				matched[j], changed[i] = true, false
+				break
+			}
+		}
+		if !changed[i] {
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("NAT rule #%d: %w", i, err)
+		}
+		if scope == nil {
+			gateway, err := c.GetGateway(gatewayID)
+			if err != nil {
+				return err
+			}
+			networks, err := c.GetNetworkList()
+			if err != nil {
+				return err
+			}
+			scope = newGatewayNATScope(gateway, networks)
+		}
+		if err := scope.check(rule); err != nil {
+			return fmt.Errorf("NAT rule #%d: %w", i, err)
+		}
+	}
+	var conflicts []*NATConflict
+	for _, conflict := range FindNATConflicts(rules) {
+		if changed[conflict.First] || changed[conflict.Second] {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	if len(conflicts) > 0 {
+		return NewNATConflictError(conflicts)
+	}
+	return nil
+}
+
+func natRulesConflict(a, b *NATRule) string {
+	if a.Equal(b) {
+		return "duplicate rule"
+	}
+	samePublic := normalizeRuleAddress(a.Destination) == normalizeRuleAddress(b.Destination)
+	switch {
+	case a.RuleType == NATRuleTypeDNAT && b.RuleType == NATRuleTypeDNAT:
+		if samePublic && protocolsOverlap(a.Protocol, b.Protocol) && natPortsOverlap(a.DestinationPort, b.DestinationPort) {
+			return fmt.Sprintf("both forward %s port %d", a.Destination, a.DestinationPort)
+		}
+	case a.RuleType == NATRuleTypeBINAT && b.RuleType == NATRuleTypeBINAT:
+		if samePublic {
+			return fmt.Sprintf("both map public IP %s", a.Destination)
+		}
+		if normalizeRuleAddress(a.Translated) == normalizeRuleAddress(b.Translated) {
+			return fmt.Sprintf("both map private IP %s", a.Translated)
+		}
+	case a.RuleType == NATRuleTypeBINAT && b.RuleType == NATRuleTypeDNAT,
+		a.RuleType == NATRuleTypeDNAT && b.RuleType == NATRuleTypeBINAT:
+		if samePublic {
+			return fmt.Sprintf("public IP %s is used by both BINAT and DNAT", a.Destination)
+		}
+	}
+	return ""
+}
+
+func natRulesEqual(a, b []*NATRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func protocolsOverlap(a, b ProtoType) bool {
+	a, b = normalizeProtocol(a), normalizeProtocol(b)
+	return a == ProtocolIP || b == ProtocolIP || a == b
+}
+
+func natPortsOverlap(a, b int) bool {
+	return a == 0 || b == 0 || a == b
+}
+
+func validateNATAddress(field string, address string) error {
+	if address == "" {
+		return fmt.Errorf("NAT rule requires a %s address", field)
+	}
+	if _, err := netip.ParseAddr(address); err != nil {
+		return fmt.Errorf("invalid %s address '%s'", field, address)
+	}
+	return nil
+}
+
+func prefixInAny(prefix netip.Prefix, prefixes []netip.Prefix) bool {
+	for _, outer := range prefixes {
+		if outer.IsValid() && prefixCovers(outer, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -45,6 +45,13 @@ func (c *SSClient) CreateNetwork(
 	networkPrefix string,
 	mask int,
 ) (*TaskIDWrap, error) {
+	ipam, err := c.LoadIPAM("0.0.0.0/0")
+	if err != nil {
+		return nil, err
+	}
+	if err := ipam.Validate(locationID, networkPrefix, mask); err != nil {
+		return nil, err
+	}
 	payload := map[string]interface{}{
 		"name":           name,
 		"location_id":    locationID,
@@ -95,11 +102,52 @@ func (c *SSClient) UpdateNetworkAndWait(networkID, name, description string) (*N
 }
 
 func (c *SSClient) DeleteNetwork(networkID string) error {
-	url := getNetworkURL(networkID)
-	_, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	_, err := c.deleteNetwork(networkID)
 	return err
 }
 
+func (c *SSClient) DeleteNetworkAndWait(networkID string) error {
+	taskWrap, err := c.deleteNetwork(networkID)
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetNetwork(networkID)
+		return err
+	})
+}
+
+func (c *SSClient) deleteNetwork(networkID string) (*TaskIDWrap, error) {
+	url := getNetworkURL(networkID)
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+func (c *SSClient) AttachServer(networkID, serverID string) (*NICEntity, error) {
+	nic, err := c.findServerNIC(serverID, networkID)
+	if err != nil {
+		return nil, err
+	}
+	if nic != nil {
+		return nic, nil
+	}
+	return c.CreateNICAndWait(serverID, networkID, 0)
+}
+
+func (c *SSClient) DetachServer(networkID, serverID string) error {
+	nic, err := c.findServerNIC(serverID, networkID)
+	if err != nil {
+		return err
+	}
+	if nic == nil {
+		return fmt.Errorf("server '%s' isn't attached to network '%s'", serverID, networkID)
+	}
+	return c.DeleteNICAndWait(serverID, nic.ID)
+}
+
 func (c *SSClient) waitNetwork(taskID string) (*NetworkEntity, error) {
 	task, err := c.waitTaskCompletion(taskID)
 	if err != nil {
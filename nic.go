@@ -1,6 +1,10 @@
 package goss
 
-import "fmt"
+import (
+	"fmt"
+	"log"
+	"net/netip"
+)
 
 type NetworkType string
 
@@ -74,6 +78,30 @@ func (c *SSClient) CreateNICAndWait(serverID, networkID string, bandwidth int) (
 	return c.waitNIC(serverID, taskWrap.ID)
 }
 
+func (c *SSClient) CreateIsolatedNIC(serverID, networkID, ipAddress string) (*TaskIDWrap, error) {
+	payload := map[string]interface{}{
+		"network_id": networkID,
+	}
+	if ipAddress != "" {
+		payload["ip_address"] = ipAddress
+	}
+
+	url := getNICSBaseURL(serverID)
+	resp, err := makeRequest(c.client, url, methodPost, payload, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+func (c *SSClient) CreateIsolatedNICAndWait(serverID, networkID, ipAddress string) (*NICEntity, error) {
+	taskWrap, err := c.CreateIsolatedNIC(serverID, networkID, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitNIC(serverID, taskWrap.ID)
+}
+
 func (c *SSClient) UpdatePublicNIC(serverID string, nicID, bandwidth int) (*TaskIDWrap, error) {
 	payload := map[string]interface{}{
 		"bandwidth_mbps": bandwidth,
@@ -95,8 +123,7 @@ func (c *SSClient) UpdatePublicNICAndWait(serverID string, nicID, bandwidth int)
 }
 
 func (c *SSClient) DeleteNIC(serverID string, nicID int) error {
-	url := getNICURL(serverID, nicID)
-	if _, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{}); err != nil {
+	if _, err := c.deleteNIC(serverID, nicID); err != nil {
 		return err
 	}
 	if _, err := c.waitServerActive(serverID); err != nil {
@@ -105,6 +132,164 @@ func (c *SSClient) DeleteNIC(serverID string, nicID int) error {
 	return nil
 }
 
+func (c *SSClient) DeleteNICAndWait(serverID string, nicID int) error {
+	taskWrap, err := c.deleteNIC(serverID, nicID)
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetNIC(serverID, nicID)
+		return err
+	})
+}
+
+func (c *SSClient) deleteNIC(serverID string, nicID int) (*TaskIDWrap, error) {
+	url := getNICURL(serverID, nicID)
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+// FindNICByIP looks up the NIC holding ipAddress. Private addresses may repeat
+// across isolated networks, so networkID narrows the search; an empty one
+// searches everywhere and fails if the address is ambiguous.
+func (c *SSClient) FindNICByIP(networkID, ipAddress string) (*ServerResponse, *NICEntity, error) {
+	server, nic, err := c.findNICByIP(networkID, ipAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	if nic == nil {
+		return nil, nil, fmt.Errorf("NIC with IP address '%s' wasn't found", ipAddress)
+	}
+	return server, nic, nil
+}
+
+func (c *SSClient) findNICByIP(networkID, ipAddress string) (*ServerResponse, *NICEntity, error) {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	servers, err := c.GetServerList()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		foundServer *ServerResponse
+		foundNIC    *NICEntity
+	)
+	for _, server := range servers {
+		for _, nic := range server.NICS {
+			if networkID != "" && nic.NetworkID != networkID {
+				continue
+			}
+			nicAddr, err := netip.ParseAddr(nic.IPAddress)
+			if err != nil || nicAddr != addr {
+				continue
+			}
+			if foundNIC != nil {
+				return nil, nil, fmt.Errorf(
+					"IP address '%s' is used by several NICs (servers '%s' and '%s'), specify a network",
+					ipAddress, foundServer.ID, server.ID,
+				)
+			}
+			foundServer, foundNIC = server, nic
+		}
+	}
+	return foundServer, foundNIC, nil
+}
+
+func (c *SSClient) MoveNIC(serverID string, nicID int, networkID, ipAddress string) (*NICEntity, error) {
+	nic, err := c.GetNIC(serverID, nicID)
+	if err != nil {
+		return nil, err
+	}
+	if nic.NetworkType != IsolatedNetwork {
+		return nil, fmt.Errorf("NIC '%d' isn't connected to an isolated network", nicID)
+	}
+	if nic.NetworkID == networkID {
+		return nic, nil
+	}
+	if ipAddress != "" {
+		if err := c.checkNICAddress(networkID, ipAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.DeleteNICAndWait(serverID, nicID); err != nil {
+		return nil, err
+	}
+	newNIC, err := c.CreateIsolatedNICAndWait(serverID, networkID, ipAddress)
+	if err != nil {
+		if _, restoreErr := c.CreateIsolatedNICAndWait(serverID, nic.NetworkID, nic.IPAddress); restoreErr != nil {
+			log.Default().Printf(
+				"[ERROR] Failed to restore NIC of server '%s' in network '%s': %s",
+				serverID, nic.NetworkID, restoreErr,
+			)
+		}
+		return nil, err
+	}
+	if ipAddress != "" && newNIC.IPAddress != ipAddress {
+		log.Default().Printf(
+			"[WARN] NIC '%d' got IP address '%s' instead of requested '%s'",
+			newNIC.ID, newNIC.IPAddress, ipAddress,
+		)
+	}
+	return newNIC, nil
+}
+
+func (c *SSClient) MoveServerToNetwork(serverID, fromNetworkID, toNetworkID, ipAddress string) (*NICEntity, error) {
+	nic, err := c.findServerNIC(serverID, fromNetworkID)
+	if err != nil {
+		return nil, err
+	}
+	if nic == nil {
+		return nil, fmt.Errorf("server '%s' isn't connected to network '%s'", serverID, fromNetworkID)
+	}
+	return c.MoveNIC(serverID, nic.ID, toNetworkID, ipAddress)
+}
+
+func (c *SSClient) checkNICAddress(networkID, ipAddress string) error {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return err
+	}
+	network, err := c.GetNetwork(networkID)
+	if err != nil {
+		return err
+	}
+	prefix, err := network.Prefix()
+	if err != nil {
+		return err
+	}
+	if !prefix.Contains(addr) {
+		return fmt.Errorf("IP address '%s' is outside of network '%s' (%s)", ipAddress, networkID, prefix)
+	}
+	_, nic, err := c.findNICByIP(networkID, ipAddress)
+	if err != nil {
+		return err
+	}
+	if nic != nil {
+		return fmt.Errorf("IP address '%s' is already used in network '%s'", ipAddress, networkID)
+	}
+	return nil
+}
+
+func (c *SSClient) findServerNIC(serverID, networkID string) (*NICEntity, error) {
+	nics, err := c.GetNICList(serverID)
+	if err != nil {
+		return nil, err
+	}
+	for _, nic := range nics {
+		if nic.NetworkID == networkID {
+			return nic, nil
+		}
+	}
+	return nil, nil
+}
+
 func getNICURL(serverID string, nicID int) string {
 	nicBaseURL := getNICSBaseURL(serverID)
 	return fmt.Sprintf("%s/%d", nicBaseURL, nicID)
@@ -0,0 +1,250 @@
+package goss
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const priceBaseURL = "prices"
+
+type (
+	// All prices are monthly, RAM and volumes are priced per GB.
+	LocationPrice struct {
+		LocationID          string  `json:"location_id"`
+		Currency            string  `json:"currency"`
+		CPU                 float64 `json:"cpu"`
+		RAMPerGB            float64 `json:"ram_gb"`
+		VolumePerGB         float64 `json:"volume_gb"`
+		BandwidthPerMbps    float64 `json:"bandwidth_mbps"`
+		PublicIP            float64 `json:"public_ip"`
+		Gateway             float64 `json:"gateway"`
+		KubernetesCluster   float64 `json:"kubernetes_cluster"`
+		KubernetesHACluster float64 `json:"kubernetes_ha_cluster"`
+	}
+
+	PriceTable struct {
+		Prices []*LocationPrice `json:"prices"`
+	}
+
+	locationPriceResponseWrap struct {
+		Price *LocationPrice `json:"price,omitempty"`
+	}
+
+	CostItem struct {
+		Kind       string  `json:"kind"`
+		ID         string  `json:"id,omitempty"`
+		Name       string  `json:"name,omitempty"`
+		LocationID string  `json:"location_id"`
+		Monthly    float64 `json:"monthly"`
+	}
+
+	CostEstimate struct {
+		Currency string      `json:"currency"`
+		Items    []*CostItem `json:"items"`
+		Total    float64     `json:"total"`
+	}
+)
+
+func (c *SSClient) GetLocationPrice(locationID string) (*LocationPrice, error) {
+	url := fmt.Sprintf("%s/%s", priceBaseURL, locationID)
+	resp, err := makeRequest(c.client, url, methodGet, nil, &locationPriceResponseWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*locationPriceResponseWrap).Price, nil
+}
+
+func (c *SSClient) GetPriceList() (*PriceTable, error) {
+	resp, err := makeRequest(c.client, priceBaseURL, methodGet, nil, &PriceTable{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*PriceTable), nil
+}
+
+func LoadPriceTable(path string) (*PriceTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	table := &PriceTable{}
+	if err := json.Unmarshal(data, table); err != nil {
+		return nil, fmt.Errorf("invalid price table '%s': %w", path, err)
+	}
+	return table, nil
+}
+
+func (t *PriceTable) Location(locationID string) (*LocationPrice, error) {
+	for _, price := range t.Prices {
+		if price.LocationID == locationID {
+			return price, nil
+		}
+	}
+	return nil, fmt.Errorf("no prices for location '%s'", locationID)
+}
+
+func (t *PriceTable) EstimateServer(
+	locationID string,
+	cpu int,
+	ram int,
+	volumes []*VolumeData,
+	networks []*NetworkData,
+) (float64, error) {
+	price, err := t.Location(locationID)
+	if err != nil {
+		return 0, err
+	}
+	cost := float64(cpu)*price.CPU + mbToGB(ram)*price.RAMPerGB
+	for _, volume := range volumes {
+		cost += mbToGB(volume.SizeMB) * price.VolumePerGB
+	}
+	for _, network := range networks {
+		if network.NetworkID == "" {
+			cost += price.PublicIP + float64(network.Bandwidth)*price.BandwidthPerMbps
+		}
+	}
+	return cost, nil
+}
+
+// A gateway always holds a public IP address, which is charged on top.
+func (t *PriceTable) EstimateGateway(locationID string, bandwidth int) (float64, error) {
+	price, err := t.Location(locationID)
+	if err != nil {
+		return 0, err
+	}
+	return price.Gateway + price.PublicIP + float64(bandwidth)*price.BandwidthPerMbps, nil
+}
+
+func (t *PriceTable) EstimateKubernetesCluster(
+	locationID string,
+	highAvailability bool,
+	nodeGroups []*KubernetesNodeGroupEntity,
+) (float64, error) {
+	price, err := t.Location(locationID)
+	if err != nil {
+		return 0, err
+	}
+	cost := price.KubernetesCluster
+	if highAvailability {
+		cost = price.KubernetesHACluster
+	}
+	for _, group := range nodeGroups {
+		nodeCost := float64(group.CPUPerNode)*price.CPU + mbToGB(group.RAMPerNode)*price.RAMPerGB
+		cost += nodeCost * float64(group.NumberOfNodes)
+	}
+	return cost, nil
+}
+
+func (c *SSClient) EstimateAccountBill(table *PriceTable) (*CostEstimate, error) {
+	estimate := &CostEstimate{}
+
+	clusters, err := c.GetKubernetesClusterList()
+	if err != nil {
+		return nil, err
+	}
+	clusterNodes := make(map[string]bool)
+	for _, cluster := range clusters {
+		cost, err := table.EstimateKubernetesCluster(cluster.LocationID, cluster.HighAvailability, cluster.NodeGroups)
+		if err != nil {
+			return nil, err
+		}
+		if err := estimate.add(table, "kubernetes_cluster", cluster.ID, cluster.Name, cluster.LocationID, cost); err != nil {
+			return nil, err
+		}
+		for _, group := range cluster.NodeGroups {
+			for _, node := range group.Nodes {
+				clusterNodes[node] = true
+			}
+		}
+	}
+
+	servers, err := c.GetServerList()
+	if err != nil {
+		return nil, err
+	}
+	for _, server := range servers {
+		if clusterNodes[server.ID] {
+			continue
+		}
+		volumes := make([]*VolumeData, 0, len(server.Volumes))
+		for _, volume := range server.Volumes {
+			volumes = append(volumes, &VolumeData{Name: volume.Name, SizeMB: volume.Size})
+		}
+		cost, err := table.EstimateServer(server.LocationID, server.CPU, server.RAM, volumes, publicNetworkData(server.NICS))
+		if err != nil {
+			return nil, err
+		}
+		if err := estimate.add(table, "server", server.ID, server.Name, server.LocationID, cost); err != nil {
+			return nil, err
+		}
+	}
+
+	gateways, err := c.GetGatewayList()
+	if err != nil {
+		return nil, err
+	}
+	for _, gateway := range gateways {
+		bandwidth := 0
+		for _, network := range publicNetworkData(gateway.NICS) {
+			bandwidth += network.Bandwidth
+		}
+		cost, err := table.EstimateGateway(gateway.LocationID, bandwidth)
+		if err != nil {
+			return nil, err
+		}
+		if err := estimate.add(table, "gateway", gateway.ID, gateway.Name, gateway.LocationID, cost); err != nil {
+			return nil, err
+		}
+	}
+
+	return estimate, nil
+}
+
+func (e *CostEstimate) String() string {
+	var b strings.Builder
+	for _, item := range e.Items {
+		fmt.Fprintf(&b, "%-20s %-40s %10.2f %s\n", item.Kind, item.Name, item.Monthly, e.Currency)
+	}
+	fmt.Fprintf(&b, "%-61s %10.2f %s\n", "Total", e.Total, e.Currency)
+	return b.String()
+}
+
+func (e *CostEstimate) add(table *PriceTable, kind, id, name, locationID string, monthly float64) error {
+	price, err := table.Location(locationID)
+	if err != nil {
+		return err
+	}
+	if e.Currency == "" {
+		e.Currency = price.Currency
+	} else if price.Currency != e.Currency {
+		return fmt.Errorf(
+			"location '%s' is priced in %s, estimate uses %s",
+			locationID, price.Currency, e.Currency,
+		)
+	}
+	e.Items = append(e.Items, &CostItem{
+		Kind:       kind,
+		ID:         id,
+		Name:       name,
+		LocationID: locationID,
+		Monthly:    monthly,
+	})
+	e.Total += monthly
+	return nil
+}
+
+func publicNetworkData(nics []*NICEntity) []*NetworkData {
+	var networks []*NetworkData
+	for _, nic := range nics {
+		if nic.NetworkType == PublicSharedNetwork {
+			networks = append(networks, &NetworkData{Bandwidth: nic.BandwidthMBPS})
+		}
+	}
+	return networks
+}
+
+func mbToGB(mb int) float64 {
+	return float64(mb) / 1024
+}
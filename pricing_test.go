@@ -0,0 +1,115 @@
+package goss
+
+import "testing"
+
+func testPriceTable() *PriceTable {
+	return &PriceTable{Prices: []*LocationPrice{{
+		LocationID:          "spb",
+		Currency:            "USD",
+		CPU:                 4,
+		RAMPerGB:            2,
+		VolumePerGB:         0.5,
+		BandwidthPerMbps:    0.25,
+		PublicIP:            3,
+		Gateway:             10,
+		KubernetesCluster:   20,
+		KubernetesHACluster: 50,
+	}}}
+}
+
+func TestEstimateServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		cpu      int
+		ram      int
+		volumes  []*VolumeData
+		networks []*NetworkData
+		want     float64
+		wantErr  bool
+	}{
+		{name: "cpu and ram", location: "spb", cpu: 2, ram: 4096, want: 16},
+		{
+			name: "volumes", location: "spb", cpu: 1, ram: 1024,
+			volumes: []*VolumeData{{SizeMB: 20480}, {SizeMB: 10240}},
+			want:    21,
+		},
+		{
+			name: "public and isolated networks", location: "spb", cpu: 1, ram: 1024,
+			networks: []*NetworkData{{Bandwidth: 40}, {NetworkID: "net", Bandwidth: 1000}},
+			want:     19,
+		},
+		{name: "unknown location", location: "msk", cpu: 1, ram: 1024, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testPriceTable().EstimateServer(tt.location, tt.cpu, tt.ram, tt.volumes, tt.networks)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EstimateServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("EstimateServer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateGateway(t *testing.T) {
+	got, err := testPriceTable().EstimateGateway("spb", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 38.0; got != want {
+		t.Errorf("EstimateGateway() = %v, want %v", got, want)
+	}
+	if _, err := testPriceTable().EstimateGateway("msk", 100); err == nil {
+		t.Error("EstimateGateway() expected an error for an unknown location")
+	}
+}
+
+func TestEstimateKubernetesCluster(t *testing.T) {
+	nodeGroups := []*KubernetesNodeGroupEntity{
+		{CPUPerNode: 2, RAMPerNode: 4096, NumberOfNodes: 3},
+		{CPUPerNode: 4, RAMPerNode: 8192, NumberOfNodes: 1},
+	}
+	tests := []struct {
+		name             string
+		highAvailability bool
+		nodeGroups       []*KubernetesNodeGroupEntity
+		want             float64
+	}{
+		{name: "control plane only", want: 20},
+		{name: "high availability", highAvailability: true, want: 50},
+		{name: "node groups", nodeGroups: nodeGroups, want: 20 + 3*16 + 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testPriceTable().EstimateKubernetesCluster("spb", tt.highAvailability, tt.nodeGroups)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("EstimateKubernetesCluster() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCostEstimateCurrency(t *testing.T) {
+	table := testPriceTable()
+	table.Prices = append(table.Prices, &LocationPrice{LocationID: "ams", Currency: "EUR"})
+
+	estimate := &CostEstimate{}
+	if err := estimate.add(table, "server", "1", "a", "spb", 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := estimate.add(table, "server", "2", "b", "spb", 5); err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Total != 15 || estimate.Currency != "USD" {
+		t.Errorf("estimate = %v %s, want 15 USD", estimate.Total, estimate.Currency)
+	}
+	if err := estimate.add(table, "server", "3", "c", "ams", 1); err == nil {
+		t.Error("add() expected an error for a different currency")
+	}
+}
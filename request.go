@@ -128,3 +128,29 @@ func makeRequest(
 
 	return respBody, nil
 }
+
+func makeRawRequest(
+	client *resty.Client,
+	url string,
+	method methodType,
+	headers map[string]string,
+	body []byte,
+	result interface{},
+) (interface{}, error) {
+	request := client.R().SetError(&ErrorBodyResponse{}).SetHeaders(headers).SetBody(body)
+	if result != nil {
+		request = request.SetResult(result)
+	}
+	log.Default().Printf("[DEBUG]  Raw request: %s %s (%d bytes)", method, url, len(body))
+
+	resp, err := request.Execute(method.String(), url)
+	if err != nil {
+		return nil, NewRequestError(resp, err)
+	}
+	log.Default().Printf("[DEBUG]  Performed raw request: %s %s: %s", method, url, resp.Status())
+
+	if resp.IsError() {
+		return nil, NewRequestError(resp, nil)
+	}
+	return resp.Result(), nil
+}
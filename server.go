@@ -51,6 +51,8 @@ func (c *SSClient) GetServer(serverID string) (*ServerResponse, error) {
 	return resp.(*serverResponseWrap).Server, nil
 }
 
+// imageID is either a public image ID or a custom image ID,
+// see CreateServerFromCustomImage.
 func (c *SSClient) CreateServer(
 	name string,
 	locationID string,
@@ -118,11 +120,30 @@ func (c *SSClient) UpdateServerAndWait(serverID string, cpu int, ram int) (*Serv
 }
 
 func (c *SSClient) DeleteServer(serverID string) error {
-	url := fmt.Sprintf("%s/%s", serverBaseURL, serverID)
-	_, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	_, err := c.deleteServer(serverID)
 	return err
 }
 
+func (c *SSClient) DeleteServerAndWait(serverID string) error {
+	taskWrap, err := c.deleteServer(serverID)
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetServer(serverID)
+		return err
+	})
+}
+
+func (c *SSClient) deleteServer(serverID string) (*TaskIDWrap, error) {
+	url := fmt.Sprintf("%s/%s", serverBaseURL, serverID)
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
 func (c *SSClient) waitServer(taskID string) (*ServerResponse, error) {
 	task, err := c.waitTaskCompletion(taskID)
 	if err != nil {
@@ -0,0 +1,211 @@
+package goss
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+type FirewallLintKind string
+
+const (
+	FirewallLintInvalid   FirewallLintKind = "invalid"
+	FirewallLintShadowed  FirewallLintKind = "shadowed"
+	FirewallLintRedundant FirewallLintKind = "redundant"
+)
+
+type (
+	Packet struct {
+		Direction       FirewallDirection
+		Protocol        ProtoType
+		Source          string
+		SourcePort      int
+		Destination     string
+		DestinationPort int
+	}
+
+	// RuleIndex is -1 when no rule matched and the default action applied.
+	// Invalid rules are skipped, as by LintFirewallRules, and listed in Skipped.
+	FirewallVerdict struct {
+		Action    FirewallAction
+		RuleIndex int
+		Rule      *FirewallRule
+		Skipped   []*FirewallLintIssue
+	}
+
+	FirewallLintIssue struct {
+		Kind       FirewallLintKind
+		RuleIndex  int
+		Rule       *FirewallRule
+		ShadowedBy int
+		Message    string
+	}
+
+	firewallMatcher struct {
+		rule        *FirewallRule
+		source      netip.Prefix
+		destination netip.Prefix
+	}
+)
+
+func EvaluateFirewall(rules []*FirewallRule, packet *Packet, defaultAction FirewallAction) (*FirewallVerdict, error) {
+	source, err := netip.ParseAddr(packet.Source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid packet source '%s'", packet.Source)
+	}
+	destination, err := netip.ParseAddr(packet.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid packet destination '%s'", packet.Destination)
+	}
+
+	verdict := &FirewallVerdict{Action: defaultAction, RuleIndex: -1}
+	for i, rule := range rules {
+		matcher, err := newFirewallMatcher(rule)
+		if err != nil {
+			verdict.Skipped = append(verdict.Skipped, &FirewallLintIssue{
+				Kind:       FirewallLintInvalid,
+				RuleIndex:  i,
+				Rule:       rule,
+				ShadowedBy: -1,
+				Message:    err.Error(),
+			})
+			continue
+		}
+		if matcher.matches(packet, source, destination) {
+			verdict.Action, verdict.RuleIndex, verdict.Rule = rule.Action, i, rule
+			break
+		}
+	}
+	return verdict, nil
+}
+
+func (g *GatewayEntity) Evaluate(packet *Packet, defaultAction FirewallAction) (*FirewallVerdict, error) {
+	return EvaluateFirewall(g.FirewallRules, packet, defaultAction)
+}
+
+func LintFirewallRules(rules []*FirewallRule) []*FirewallLintIssue {
+	var issues []*FirewallLintIssue
+	matchers := make([]*firewallMatcher, len(rules))
+	for i, rule := range rules {
+		matcher, err := newFirewallMatcher(rule)
+		if err != nil {
+			issues = append(issues, &FirewallLintIssue{
+				Kind:       FirewallLintInvalid,
+				RuleIndex:  i,
+				Rule:       rule,
+				ShadowedBy: -1,
+				Message:    err.Error(),
+			})
+			continue
+		}
+		matchers[i] = matcher
+
+		for j := 0; j < i; j++ {
+			if matchers[j] == nil || !matchers[j].covers(matcher) {
+				continue
+			}
+			issue := &FirewallLintIssue{
+				Kind:       FirewallLintShadowed,
+				RuleIndex:  i,
+				Rule:       rule,
+				ShadowedBy: j,
+				Message:    fmt.Sprintf("rule #%d is unreachable, rule #%d matches all its traffic", i, j),
+			}
+			if rules[j].Action == rule.Action {
+				issue.Kind = FirewallLintRedundant
+				issue.Message = fmt.Sprintf("rule #%d is redundant, rule #%d already applies %s to its traffic", i, j, rule.Action)
+			}
+			issues = append(issues, issue)
+			break
+		}
+	}
+	return issues
+}
+
+// Index of the first Deny rule that matches all traffic of rule, so that
+// inserting rule there keeps it reachable; len(rules) if there is none.
+func firewallInsertIndex(rules []*FirewallRule, rule *FirewallRule) (int, error) {
+	matcher, err := newFirewallMatcher(rule)
+	if err != nil {
+		return 0, err
+	}
+	for i, existing := range rules {
+		if existing.Action != FirewallActionDeny {
+			continue
+		}
+		if deny, err := newFirewallMatcher(existing); err == nil && deny.covers(matcher) {
+			return i, nil
+		}
+	}
+	return len(rules), nil
+}
+
+func newFirewallMatcher(rule *FirewallRule) (*firewallMatcher, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	matcher := &firewallMatcher{rule: rule}
+	if rule.Source != "" {
+		matcher.source, _ = parseRuleAddress(rule.Source)
+	}
+	if rule.Destination != "" {
+		matcher.destination, _ = parseRuleAddress(rule.Destination)
+	}
+	return matcher, nil
+}
+
+func (m *firewallMatcher) matches(packet *Packet, source, destination netip.Addr) bool {
+	rule := m.rule
+	if rule.Direction != packet.Direction {
+		return false
+	}
+	if protocol := normalizeProtocol(rule.Protocol); protocol != ProtocolIP && protocol != normalizeProtocol(packet.Protocol) {
+		return false
+	}
+	if m.source.IsValid() && !m.source.Contains(source) {
+		return false
+	}
+	if m.destination.IsValid() && !m.destination.Contains(destination) {
+		return false
+	}
+	if !isAnyPort(rule.SourcePort) && !rule.SourcePort.Contains(packet.SourcePort) {
+		return false
+	}
+	if !isAnyPort(rule.DestinationPort) && !rule.DestinationPort.Contains(packet.DestinationPort) {
+		return false
+	}
+	return true
+}
+
+func (m *firewallMatcher) covers(other *firewallMatcher) bool {
+	if m.rule.Direction != other.rule.Direction {
+		return false
+	}
+	protocol := normalizeProtocol(m.rule.Protocol)
+	if protocol != ProtocolIP && protocol != normalizeProtocol(other.rule.Protocol) {
+		return false
+	}
+	return prefixCovers(m.source, other.source) &&
+		prefixCovers(m.destination, other.destination) &&
+		portRangeCovers(m.rule.SourcePort, other.rule.SourcePort) &&
+		portRangeCovers(m.rule.DestinationPort, other.rule.DestinationPort)
+}
+
+func prefixCovers(outer, inner netip.Prefix) bool {
+	if !outer.IsValid() || outer.Bits() == 0 {
+		return true
+	}
+	if !inner.IsValid() {
+		return false
+	}
+	return outer.Bits() <= inner.Bits() && outer.Contains(inner.Addr())
+}
+
+func portRangeCovers(outer, inner *PortRange) bool {
+	if isAnyPort(outer) {
+		return true
+	}
+	if isAnyPort(inner) {
+		return false
+	}
+	return outer.From <= inner.From && inner.To <= outer.To
+}
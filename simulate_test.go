@@ -0,0 +1,238 @@
+package goss
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateFirewall(t *testing.T) {
+	rules := []*FirewallRule{
+		{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolIP, Source: "192.168.1.10"},
+		{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(22)},
+		{
+			Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolUDP,
+			Source: "10.0.0.0/8", DestinationPort: Ports(5000, 6000),
+		},
+		{Action: FirewallActionDeny, Direction: FirewallDirectionOut, Protocol: ProtocolIP, Destination: "8.8.8.8/32"},
+	}
+
+	tests := []struct {
+		name       string
+		packet     *Packet
+		wantAction FirewallAction
+		wantIndex  int
+	}{
+		{
+			name: "first match wins",
+			packet: &Packet{
+				Direction: FirewallDirectionIn, Protocol: ProtocolTCP,
+				Source: "192.168.1.10", Destination: "10.0.0.1", DestinationPort: 22,
+			},
+			wantAction: FirewallActionDeny,
+			wantIndex:  0,
+		},
+		{
+			name: "port match",
+			packet: &Packet{
+				Direction: FirewallDirectionIn, Protocol: ProtocolTCP,
+				Source: "1.2.3.4", Destination: "10.0.0.1", DestinationPort: 22,
+			},
+			wantAction: FirewallActionAllow,
+			wantIndex:  1,
+		},
+		{
+			name: "protocol mismatch falls through",
+			packet: &Packet{
+				Direction: FirewallDirectionIn, Protocol: ProtocolUDP,
+				Source: "1.2.3.4", Destination: "10.0.0.1", DestinationPort: 22,
+			},
+			wantAction: FirewallActionDeny,
+			wantIndex:  -1,
+		},
+		{
+			name: "source prefix and port range",
+			packet: &Packet{
+				Direction: FirewallDirectionIn, Protocol: ProtocolUDP,
+				Source: "10.1.2.3", Destination: "10.0.0.1", DestinationPort: 5353,
+			},
+			wantAction: FirewallActionAllow,
+			wantIndex:  2,
+		},
+		{
+			name: "port out of range",
+			packet: &Packet{
+				Direction: FirewallDirectionIn, Protocol: ProtocolUDP,
+				Source: "10.1.2.3", Destination: "10.0.0.1", DestinationPort: 6001,
+			},
+			wantAction: FirewallActionDeny,
+			wantIndex:  -1,
+		},
+		{
+			name: "direction",
+			packet: &Packet{
+				Direction: FirewallDirectionOut, Protocol: ProtocolUDP,
+				Source: "10.0.0.1", Destination: "8.8.8.8", DestinationPort: 53,
+			},
+			wantAction: FirewallActionDeny,
+			wantIndex:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := EvaluateFirewall(rules, tt.packet, FirewallActionDeny)
+			if err != nil {
+				t.Fatalf("EvaluateFirewall() error = %v", err)
+			}
+			if verdict.Action != tt.wantAction || verdict.RuleIndex != tt.wantIndex {
+				t.Errorf(
+					"EvaluateFirewall() = %s #%d, want %s #%d",
+					verdict.Action, verdict.RuleIndex, tt.wantAction, tt.wantIndex,
+				)
+			}
+		})
+	}
+}
+
+func TestEvaluateFirewallSkipsInvalidRules(t *testing.T) {
+	rules := []*FirewallRule{
+		{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Source: "10.0.0.1/8"},
+		{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolIP},
+	}
+	packet := &Packet{Direction: FirewallDirectionIn, Source: "10.0.0.1", Destination: "10.0.0.2"}
+	verdict, err := EvaluateFirewall(rules, packet, FirewallActionDeny)
+	if err != nil {
+		t.Fatalf("EvaluateFirewall() error = %v", err)
+	}
+	if verdict.Action != FirewallActionAllow || verdict.RuleIndex != 1 {
+		t.Errorf("EvaluateFirewall() = %s #%d, want Allow #1", verdict.Action, verdict.RuleIndex)
+	}
+	if len(verdict.Skipped) != 1 || verdict.Skipped[0].RuleIndex != 0 || verdict.Skipped[0].Kind != FirewallLintInvalid {
+		t.Errorf("EvaluateFirewall() skipped = %+v, want rule #0", verdict.Skipped)
+	}
+}
+
+func TestEvaluateFirewallInvalidPacket(t *testing.T) {
+	packet := &Packet{Direction: FirewallDirectionIn, Protocol: ProtocolTCP, Source: "nowhere", Destination: "10.0.0.1"}
+	if _, err := EvaluateFirewall(nil, packet, FirewallActionAllow); err == nil {
+		t.Fatal("EvaluateFirewall() expected an error for an invalid source")
+	}
+}
+
+func TestLintFirewallRules(t *testing.T) {
+	type issue struct {
+		Kind       FirewallLintKind
+		RuleIndex  int
+		ShadowedBy int
+	}
+	tests := []struct {
+		name  string
+		rules []*FirewallRule
+		want  []issue
+	}{
+		{
+			name: "no issues",
+			rules: []*FirewallRule{
+				{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(22)},
+				{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(443)},
+				{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolIP},
+			},
+		},
+		{
+			name: "shadowed by broader deny",
+			rules: []*FirewallRule{
+				{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolIP, Source: "10.0.0.0/8"},
+				{
+					Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP,
+					Source: "10.1.0.0/16", DestinationPort: Port(22),
+				},
+			},
+			want: []issue{{Kind: FirewallLintShadowed, RuleIndex: 1, ShadowedBy: 0}},
+		},
+		{
+			name: "redundant port range",
+			rules: []*FirewallRule{
+				{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Ports(8000, 9000)},
+				{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(8080)},
+			},
+			want: []issue{{Kind: FirewallLintRedundant, RuleIndex: 1, ShadowedBy: 0}},
+		},
+		{
+			name: "narrower rule first",
+			rules: []*FirewallRule{
+				{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, Source: "10.1.0.0/16"},
+				{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, Source: "10.0.0.0/8"},
+			},
+		},
+		{
+			name: "other direction",
+			rules: []*FirewallRule{
+				{Action: FirewallActionDeny, Direction: FirewallDirectionOut, Protocol: ProtocolIP},
+				{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolIP},
+			},
+		},
+		{
+			name: "invalid rule",
+			rules: []*FirewallRule{
+				{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, Source: "10.0.0.1/8"},
+			},
+			want: []issue{{Kind: FirewallLintInvalid, RuleIndex: 0, ShadowedBy: -1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []issue
+			for _, lint := range LintFirewallRules(tt.rules) {
+				got = append(got, issue{Kind: lint.Kind, RuleIndex: lint.RuleIndex, ShadowedBy: lint.ShadowedBy})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LintFirewallRules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirewallInsertIndex(t *testing.T) {
+	allowWeb := &FirewallRule{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(443)}
+	tests := []struct {
+		name  string
+		rules []*FirewallRule
+		want  int
+	}{
+		{name: "no rules", want: 0},
+		{
+			name: "before catch-all deny",
+			rules: []*FirewallRule{
+				{Action: FirewallActionAllow, Direction: FirewallDirectionIn, Protocol: ProtocolTCP, DestinationPort: Port(22)},
+				{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolIP},
+			},
+			want: 1,
+		},
+		{
+			name: "partial deny is skipped",
+			rules: []*FirewallRule{
+				{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolIP, Source: "10.0.0.0/8"},
+				{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Protocol: ProtocolTCP},
+			},
+			want: 1,
+		},
+		{
+			name: "no covering deny",
+			rules: []*FirewallRule{
+				{Action: FirewallActionDeny, Direction: FirewallDirectionOut, Protocol: ProtocolIP},
+				{Action: FirewallActionDeny, Direction: FirewallDirectionIn, Source: "10.0.0.1/8"},
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := firewallInsertIndex(tt.rules, allowWeb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("firewallInsertIndex() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -29,6 +29,35 @@ func (c *SSClient) GetSnapshotList(serverID string) ([]*SnapshotEntity, error) {
 	return resp.(*snapshotListResponseWrap).Snapshots, nil
 }
 
+func (c *SSClient) GetSnapshot(serverID string, snapshotID int) (*SnapshotEntity, error) {
+	url := getSnapshotURL(serverID, snapshotID)
+	resp, err := makeRequest(c.client, url, methodGet, nil, &SnapshotEntityWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*SnapshotEntityWrap).Snapshot, nil
+}
+
+func (c *SSClient) DeleteSnapshot(serverID string, snapshotID int) (*TaskIDWrap, error) {
+	url := getSnapshotURL(serverID, snapshotID)
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
+func (c *SSClient) DeleteSnapshotAndWait(serverID string, snapshotID int) error {
+	taskWrap, err := c.DeleteSnapshot(serverID, snapshotID)
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetSnapshot(serverID, snapshotID)
+		return err
+	})
+}
+
 func getSnapshotURL(serverID string, snapshotID int) string {
 	snapshotBaseURL := getSnapshotBaseURL(serverID)
 	return fmt.Sprintf("%s/%d", snapshotBaseURL, snapshotID)
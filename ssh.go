@@ -34,6 +34,9 @@ func (c *SSClient) CreateSSHKey(
 	name string,
 	publicKey string,
 ) (*SSHResponse, error) {
+	if _, err := ParseSSHPublicKey(publicKey); err != nil {
+		return nil, err
+	}
 	payload := map[string]interface{}{
 		"name":       name,
 		"public_key": publicKey,
@@ -46,6 +49,34 @@ func (c *SSClient) CreateSSHKey(
 	return resp.(*SSHResponse), nil
 }
 
+func (c *SSClient) UpdateSSHKey(sshID int, name string) (*SSHResponse, error) {
+	url := fmt.Sprintf("%s/%d", sshBaseURL, sshID)
+	payload := map[string]interface{}{
+		"name": name,
+	}
+
+	resp, err := makeRequest(c.client, url, methodPut, payload, &SSHResponse{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*SSHResponse), nil
+}
+
+func (c *SSClient) EnsureSSHKey(name string, publicKey string) (*SSHResponse, error) {
+	key, err := ParseSSHPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := c.findSSHKeyByFingerprint(key.FingerprintSHA256())
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	return c.CreateSSHKey(name, publicKey)
+}
+
 func (c *SSClient) DeleteSSHKey(sshID int) error {
 	url := fmt.Sprintf("%s/%d", sshBaseURL, sshID)
 	_, err := makeRequest(c.client, url, methodDelete, nil, nil)
@@ -59,3 +90,17 @@ func (c *SSClient) GetSSHKeyList() ([]*SSHResponse, error) {
 	}
 	return resp.(*sshListResponseWrap).SSHKeys, nil
 }
+
+func (c *SSClient) findSSHKeyByFingerprint(fingerprint string) (*SSHResponse, error) {
+	keys, err := c.GetSSHKeyList()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		keyFingerprint, err := key.Fingerprint()
+		if err == nil && keyFingerprint == fingerprint {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
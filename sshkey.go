@@ -0,0 +1,93 @@
+package goss
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+var sshKeyTypes = map[string]bool{
+	"ssh-rsa":                            true,
+	"ssh-dss":                            true,
+	"ssh-ed25519":                        true,
+	"ecdsa-sha2-nistp256":                true,
+	"ecdsa-sha2-nistp384":                true,
+	"ecdsa-sha2-nistp521":                true,
+	"sk-ssh-ed25519@openssh.com":         true,
+	"sk-ecdsa-sha2-nistp256@openssh.com": true,
+}
+
+type SSHPublicKey struct {
+	Type    string
+	Blob    []byte
+	Comment string
+}
+
+func ParseSSHPublicKey(publicKey string) (*SSHPublicKey, error) {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("public key must have '<type> <base64> [comment]' format")
+	}
+	keyType := fields[0]
+	if !sshKeyTypes[keyType] {
+		return nil, fmt.Errorf("unsupported public key type '%s'", keyType)
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("public key isn't valid base64: %w", err)
+	}
+	if len(blob) < 4 {
+		return nil, fmt.Errorf("public key data is too short")
+	}
+	typeLen := binary.BigEndian.Uint32(blob)
+	if uint64(typeLen) > uint64(len(blob)-4) || !bytes.Equal(blob[4:4+typeLen], []byte(keyType)) {
+		return nil, fmt.Errorf("public key data doesn't match key type '%s'", keyType)
+	}
+	return &SSHPublicKey{
+		Type:    keyType,
+		Blob:    blob,
+		Comment: strings.Join(fields[2:], " "),
+	}, nil
+}
+
+func (k *SSHPublicKey) FingerprintSHA256() string {
+	sum := sha256.Sum256(k.Blob)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+func (k *SSHPublicKey) FingerprintMD5() string {
+	sum := md5.Sum(k.Blob)
+	hexBytes := make([]string, len(sum))
+	for i, b := range sum {
+		hexBytes[i] = fmt.Sprintf("%02x", b)
+	}
+	return "MD5:" + strings.Join(hexBytes, ":")
+}
+
+func (k *SSHPublicKey) String() string {
+	key := k.Type + " " + base64.StdEncoding.EncodeToString(k.Blob)
+	if k.Comment != "" {
+		key += " " + k.Comment
+	}
+	return key
+}
+
+func (s *SSHResponse) Fingerprint() (string, error) {
+	key, err := ParseSSHPublicKey(s.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return key.FingerprintSHA256(), nil
+}
+
+func (s *SSHResponse) FingerprintMD5() (string, error) {
+	key, err := ParseSSHPublicKey(s.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return key.FingerprintMD5(), nil
+}
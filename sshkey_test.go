@@ -0,0 +1,81 @@
+package goss
+
+import "testing"
+
+const (
+	testED25519Key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIO/AYmXAGLt7SFiwnR7LwP4Oroginxijki7odJxidv5h alice@example"
+	testRSAKey     = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQC9eWF+U8pN+TMIAqkCYCT/S9PjsRVX3EL/9Hy3fSaHT6Msp7/fb4Ed55" +
+		"MnFwVjENR9eazkvc0grfCFOI1Qd3WAFxpHFLb6rohbNexY+eGH/qtUFrdMnfy2pQY+9gbOY0ZEHoV0Uhu6JKJ479GiGStdK6qv2l1o" +
+		"rkkxMsmzD/P5CQ=="
+)
+
+func TestParseSSHPublicKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		wantType    string
+		wantComment string
+		wantSHA256  string
+		wantMD5     string
+		wantErr     bool
+	}{
+		{
+			name:        "ed25519",
+			key:         testED25519Key,
+			wantType:    "ssh-ed25519",
+			wantComment: "alice@example",
+			wantSHA256:  "SHA256:fYoqq6cmdNtyoe3VXqCdwXwZ0GOnM150E9CXfERTdLM",
+			wantMD5:     "MD5:f1:7f:86:4c:b2:75:48:e6:48:7e:8d:d1:aa:17:79:96",
+		},
+		{
+			name:       "rsa without comment",
+			key:        testRSAKey,
+			wantType:   "ssh-rsa",
+			wantSHA256: "SHA256:OFyHkc9A3wWY/uSkwYJJbBjW9sgpOt+GX4kSexBvFAQ",
+			wantMD5:    "MD5:19:52:39:72:c0:5b:70:50:2c:99:5a:6a:10:9b:9d:e9",
+		},
+		{
+			name:        "extra whitespace",
+			key:         "  " + testED25519Key + " laptop\n",
+			wantType:    "ssh-ed25519",
+			wantComment: "alice@example laptop",
+			wantSHA256:  "SHA256:fYoqq6cmdNtyoe3VXqCdwXwZ0GOnM150E9CXfERTdLM",
+			wantMD5:     "MD5:f1:7f:86:4c:b2:75:48:e6:48:7e:8d:d1:aa:17:79:96",
+		},
+		{name: "missing data", key: "ssh-ed25519", wantErr: true},
+		{name: "unknown type", key: "ssh-foo AAAA", wantErr: true},
+		{name: "invalid base64", key: "ssh-ed25519 !!!!", wantErr: true},
+		{name: "type mismatch", key: "ssh-rsa " + testED25519Key[len("ssh-ed25519 "):], wantErr: true},
+		{name: "too short", key: "ssh-ed25519 AAA=", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParseSSHPublicKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSSHPublicKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if key.Type != tt.wantType || key.Comment != tt.wantComment {
+				t.Errorf("ParseSSHPublicKey() = %s %q, want %s %q", key.Type, key.Comment, tt.wantType, tt.wantComment)
+			}
+			if got := key.FingerprintSHA256(); got != tt.wantSHA256 {
+				t.Errorf("FingerprintSHA256() = %s, want %s", got, tt.wantSHA256)
+			}
+			if got := key.FingerprintMD5(); got != tt.wantMD5 {
+				t.Errorf("FingerprintMD5() = %s, want %s", got, tt.wantMD5)
+			}
+		})
+	}
+}
+
+func TestSSHPublicKeyString(t *testing.T) {
+	key, err := ParseSSHPublicKey(testED25519Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := key.String(); got != testED25519Key {
+		t.Errorf("String() = %s, want %s", got, testED25519Key)
+	}
+}
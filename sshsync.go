@@ -0,0 +1,194 @@
+package goss
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	LocalSSHKey struct {
+		Name      string
+		PublicKey *SSHPublicKey
+		Source    string
+	}
+
+	SSHKeySyncPlan struct {
+		Create    []*LocalSSHKey
+		Delete    []*SSHResponse
+		Unchanged []*SSHResponse
+	}
+)
+
+func LoadSSHKeys(path string) ([]*LocalSSHKey, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return ReadSSHKeyDir(path)
+	}
+	return ReadAuthorizedKeys(path)
+}
+
+func ReadAuthorizedKeys(path string) ([]*LocalSSHKey, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	base := strings.TrimSuffix(filepath.Base(path), ".pub")
+	var keys []*LocalSSHKey
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := parseAuthorizedKeyLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+		name := key.Comment
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", base, lineNumber)
+		}
+		keys = append(keys, &LocalSSHKey{
+			Name:      name,
+			PublicKey: key,
+			Source:    fmt.Sprintf("%s:%d", path, lineNumber),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func ReadSSHKeyDir(dir string) ([]*LocalSSHKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pub"))
+	if err != nil {
+		return nil, err
+	}
+	var keys []*LocalSSHKey
+	for _, path := range paths {
+		fileKeys, err := ReadAuthorizedKeys(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(fileKeys) == 1 {
+			fileKeys[0].Name = strings.TrimSuffix(filepath.Base(path), ".pub")
+		}
+		keys = append(keys, fileKeys...)
+	}
+	return keys, nil
+}
+
+// PlanSSHKeySync refuses to prune against an empty local key set, since an
+// unreadable or empty source would otherwise remove every key of the account.
+func (c *SSClient) PlanSSHKeySync(keys []*LocalSSHKey, prune bool) (*SSHKeySyncPlan, error) {
+	if prune && len(keys) == 0 {
+		return nil, errors.New("refusing to prune SSH keys: no local keys were found")
+	}
+	remoteKeys, err := c.GetSSHKeyList()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &SSHKeySyncPlan{}
+	remoteByFingerprint := make(map[string]*SSHResponse)
+	for _, remoteKey := range remoteKeys {
+		fingerprint, err := remoteKey.Fingerprint()
+		if err != nil {
+			log.Default().Printf("[WARN] Skip SSH key '%s' (%d): %s", remoteKey.Name, remoteKey.ID, err)
+			continue
+		}
+		remoteByFingerprint[fingerprint] = remoteKey
+	}
+
+	localFingerprints := make(map[string]bool)
+	for _, key := range keys {
+		fingerprint := key.PublicKey.FingerprintSHA256()
+		if localFingerprints[fingerprint] {
+			continue
+		}
+		localFingerprints[fingerprint] = true
+		if remoteKey, ok := remoteByFingerprint[fingerprint]; ok {
+			plan.Unchanged = append(plan.Unchanged, remoteKey)
+		} else {
+			plan.Create = append(plan.Create, key)
+		}
+	}
+
+	if prune {
+		for _, remoteKey := range remoteKeys {
+			fingerprint, err := remoteKey.Fingerprint()
+			if err == nil && !localFingerprints[fingerprint] {
+				plan.Delete = append(plan.Delete, remoteKey)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+func (c *SSClient) ApplySSHKeySync(plan *SSHKeySyncPlan) error {
+	for _, key := range plan.Create {
+		if _, err := c.CreateSSHKey(key.Name, key.PublicKey.String()); err != nil {
+			return err
+		}
+	}
+	for _, key := range plan.Delete {
+		if err := c.DeleteSSHKey(key.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *SSClient) SyncSSHKeys(path string, prune bool, dryRun bool) (*SSHKeySyncPlan, error) {
+	keys, err := LoadSSHKeys(path)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := c.PlanSSHKeySync(keys, prune)
+	if err != nil {
+		return nil, err
+	}
+	log.Default().Printf("[INFO] %s", plan)
+	if dryRun {
+		return plan, nil
+	}
+	return plan, c.ApplySSHKeySync(plan)
+}
+
+func (p *SSHKeySyncPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(
+		&b, "SSH keys: %d to create, %d to delete, %d unchanged\n",
+		len(p.Create), len(p.Delete), len(p.Unchanged),
+	)
+	for _, key := range p.Create {
+		fmt.Fprintf(&b, "  + %s %s (%s)\n", key.Name, key.PublicKey.FingerprintSHA256(), key.Source)
+	}
+	for _, key := range p.Delete {
+		fingerprint, _ := key.Fingerprint()
+		fmt.Fprintf(&b, "  - %s %s (%d)\n", key.Name, fingerprint, key.ID)
+	}
+	return b.String()
+}
+
+func parseAuthorizedKeyLine(line string) (*SSHPublicKey, error) {
+	fields := strings.Fields(line)
+	for i, field := range fields {
+		if sshKeyTypes[field] {
+			return ParseSSHPublicKey(strings.Join(fields[i:], " "))
+		}
+	}
+	return nil, fmt.Errorf("no supported public key found")
+}
@@ -0,0 +1,96 @@
+package goss
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSSHPort         = 22
+	defaultSSHPollInterval = 5 * time.Second
+)
+
+type SSHWaitOptions struct {
+	Port        int
+	Timeout     time.Duration
+	Interval    time.Duration
+	CheckBanner bool
+}
+
+func WaitForSSH(server *ServerResponse, opts *SSHWaitOptions) (string, error) {
+	ip, err := serverPublicIP(server)
+	if err != nil {
+		return "", err
+	}
+	port := defaultSSHPort
+	if opts != nil && opts.Port != 0 {
+		port = opts.Port
+	}
+	address := net.JoinHostPort(ip, strconv.Itoa(port))
+	return address, WaitForSSHAddress(address, opts)
+}
+
+func WaitForSSHAddress(address string, opts *SSHWaitOptions) error {
+	timeout := defaultTaskCompletionDuration
+	interval := defaultSSHPollInterval
+	checkBanner := false
+	if opts != nil {
+		if opts.Timeout > 0 {
+			timeout = opts.Timeout
+		}
+		if opts.Interval > 0 {
+			interval = opts.Interval
+		}
+		checkBanner = opts.CheckBanner
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := probeSSH(address, interval, checkBanner)
+		if err == nil {
+			return nil
+		}
+		log.Default().Printf("[TRACE] SSH isn't ready on %s: %s", address, err)
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("ssh on %s wasn't ready for %f secs: %w", address, timeout.Seconds(), err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func probeSSH(address string, timeout time.Duration, checkBanner bool) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if !checkBanner {
+		return nil
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	banner, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(banner, "SSH-") {
+		return fmt.Errorf("unexpected banner %q", strings.TrimSpace(banner))
+	}
+	return nil
+}
+
+func serverPublicIP(server *ServerResponse) (string, error) {
+	for _, nic := range server.NICS {
+		if nic.NetworkType == PublicSharedNetwork && nic.IPAddress != "" {
+			return nic.IPAddress, nil
+		}
+	}
+	return "", fmt.Errorf("server '%s' has no public IP address", server.ID)
+}
@@ -0,0 +1,52 @@
+package goss
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWaitForSSHAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		banner  string
+		wantErr bool
+	}{
+		{name: "ssh banner", banner: "SSH-2.0-OpenSSH_8.9\r\n"},
+		{name: "non-ssh banner", banner: "HTTP/1.1 400 Bad Request\r\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := serveBanner(t, tt.banner)
+			err := WaitForSSHAddress(address, &SSHWaitOptions{
+				Timeout:     300 * time.Millisecond,
+				Interval:    100 * time.Millisecond,
+				CheckBanner: true,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitForSSHAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func serveBanner(t *testing.T, banner string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(banner))
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
@@ -30,6 +30,7 @@ type (
 		GatewayID             string `json:"gateway_id,omitempty"`
 		KubernetesClusterID   string `json:"cluster_id,omitempty"`
 		KubernetesNodeGroupID string `json:"node_group_id,omitempty"`
+		ImageID               string `json:"image_id,omitempty"`
 	}
 
 	taskResponseWrap struct {
@@ -106,3 +107,32 @@ func (c *SSClient) waitServerActive(serverID string) (*ServerResponse, error) {
 
 	return server, err
 }
+
+func (c *SSClient) waitResourceDeletion(taskID string, getResource func() error) error {
+	const duration = defaultTaskCompletionDuration
+	if taskID != "" {
+		if _, err := c.waitTaskCompletion(taskID); err != nil {
+			return err
+		}
+	}
+
+	begin := time.Now()
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := getResource()
+		if IsNotFoundError(err) {
+			return nil
+		} else if err != nil {
+			return err
+		} else {
+			log.Default().Printf("[TRACE] Resource isn't removed after task '%s'", taskID)
+		}
+		if time.Since(begin) > duration {
+			return fmt.Errorf("resource wasn't removed for %f secs", duration.Seconds())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package goss
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type TopologyNodeKind string
+
+const (
+	TopologyServer  TopologyNodeKind = "server"
+	TopologyNIC     TopologyNodeKind = "nic"
+	TopologyNetwork TopologyNodeKind = "network"
+	TopologyGateway TopologyNodeKind = "gateway"
+	TopologyNATRule TopologyNodeKind = "nat_rule"
+)
+
+var topologyNodeShapes = map[TopologyNodeKind]string{
+	TopologyServer:  "box",
+	TopologyNIC:     "circle",
+	TopologyNetwork: "ellipse",
+	TopologyGateway: "diamond",
+	TopologyNATRule: "note",
+}
+
+type (
+	TopologyNode struct {
+		ID         string            `json:"id"`
+		Kind       TopologyNodeKind  `json:"kind"`
+		Label      string            `json:"label"`
+		Attributes map[string]string `json:"attributes,omitempty"`
+	}
+
+	TopologyEdge struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Label string `json:"label,omitempty"`
+	}
+
+	Topology struct {
+		Nodes []*TopologyNode `json:"nodes"`
+		Edges []*TopologyEdge `json:"edges"`
+
+		nodes map[string]*TopologyNode
+	}
+)
+
+func (c *SSClient) GetTopology() (*Topology, error) {
+	servers, err := c.GetServerList()
+	if err != nil {
+		return nil, err
+	}
+	networks, err := c.GetNetworkList()
+	if err != nil {
+		return nil, err
+	}
+	gateways, err := c.GetGatewayList()
+	if err != nil {
+		return nil, err
+	}
+	return BuildTopology(servers, networks, gateways), nil
+}
+
+func BuildTopology(
+	servers []*ServerResponse,
+	networks []*NetworkEntity,
+	gateways []*GatewayEntity,
+) *Topology {
+	t := &Topology{nodes: make(map[string]*TopologyNode)}
+
+	for _, network := range networks {
+		t.addNode(networkNodeID(network.ID), TopologyNetwork, network.Name, map[string]string{
+			"location_id": network.LocationID,
+			"cidr":        fmt.Sprintf("%s/%d", network.NetworkPrefix, network.Mask),
+		})
+	}
+
+	nicsByIP := make(map[string]string)
+	for _, server := range servers {
+		serverNode := "server:" + server.ID
+		t.addNode(serverNode, TopologyServer, server.Name, map[string]string{
+			"location_id": server.LocationID,
+			"state":       server.State,
+		})
+		for _, nic := range server.NICS {
+			nicNode := t.addNIC(serverNode, nic)
+			if nic.IPAddress != "" {
+				nicsByIP[nic.NetworkID+"/"+nic.IPAddress] = nicNode
+			}
+		}
+	}
+
+	for _, gateway := range gateways {
+		gatewayNode := "gateway:" + gateway.ID
+		t.addNode(gatewayNode, TopologyGateway, gateway.Name, map[string]string{
+			"location_id": gateway.LocationID,
+			"state":       gateway.State,
+		})
+		for _, nic := range gateway.NICS {
+			t.addNIC(gatewayNode, nic)
+		}
+		for _, networkID := range gateway.NetworkIDs {
+			t.ensureNetwork(networkID, IsolatedNetwork)
+			t.addEdge(gatewayNode, networkNodeID(networkID), "")
+		}
+		for i, rule := range gateway.NATRules {
+			ruleNode := fmt.Sprintf("nat:%s:%d", gateway.ID, i)
+			t.addNode(ruleNode, TopologyNATRule, rule.String(), map[string]string{
+				"type":     string(rule.RuleType),
+				"protocol": string(rule.Protocol),
+			})
+			t.addEdge(gatewayNode, ruleNode, "")
+			for _, networkID := range gateway.NetworkIDs {
+				if nicNode, ok := nicsByIP[networkID+"/"+rule.Translated]; ok {
+					t.addEdge(ruleNode, nicNode, "translated")
+					break
+				} else if nicNode, ok := nicsByIP[networkID+"/"+rule.Source]; ok {
+					t.addEdge(ruleNode, nicNode, "source")
+					break
+				}
+			}
+		}
+	}
+
+	return t
+}
+
+func (t *Topology) DOT() string {
+	var b strings.Builder
+	b.WriteString("graph topology {\n")
+	for _, node := range t.Nodes {
+		fmt.Fprintf(
+			&b, "  %s [label=%s, shape=%s];\n",
+			dotQuote(node.ID), dotQuote(node.Label), topologyNodeShapes[node.Kind],
+		)
+	}
+	for _, edge := range t.Edges {
+		if edge.Label != "" {
+			fmt.Fprintf(&b, "  %s -- %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(edge.Label))
+		} else {
+			fmt.Fprintf(&b, "  %s -- %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (t *Topology) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+func (t *Topology) addNIC(ownerNode string, nic *NICEntity) string {
+	nicNode := fmt.Sprintf("nic:%s:%d", nic.ServerID, nic.ID)
+	if nic.ServerID == "" {
+		nicNode = fmt.Sprintf("nic:%s:%d", ownerNode, nic.ID)
+	}
+	t.addNode(nicNode, TopologyNIC, nic.IPAddress, map[string]string{
+		"mac":          nic.MAC,
+		"network_type": string(nic.NetworkType),
+	})
+	t.addEdge(ownerNode, nicNode, "")
+	if nic.NetworkID != "" {
+		t.ensureNetwork(nic.NetworkID, nic.NetworkType)
+		t.addEdge(nicNode, networkNodeID(nic.NetworkID), "")
+	}
+	return nicNode
+}
+
+func (t *Topology) ensureNetwork(networkID string, networkType NetworkType) {
+	id := networkNodeID(networkID)
+	if _, ok := t.nodes[id]; ok {
+		return
+	}
+	t.addNode(id, TopologyNetwork, networkID, map[string]string{
+		"network_type": string(networkType),
+	})
+}
+
+func (t *Topology) addNode(id string, kind TopologyNodeKind, label string, attributes map[string]string) {
+	if _, ok := t.nodes[id]; ok {
+		return
+	}
+	for key, value := range attributes {
+		if value == "" {
+			delete(attributes, key)
+		}
+	}
+	node := &TopologyNode{ID: id, Kind: kind, Label: label, Attributes: attributes}
+	t.nodes[id] = node
+	t.Nodes = append(t.Nodes, node)
+}
+
+func (t *Topology) addEdge(from, to, label string) {
+	t.Edges = append(t.Edges, &TopologyEdge{From: from, To: to, Label: label})
+}
+
+func networkNodeID(networkID string) string {
+	return "network:" + networkID
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
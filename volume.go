@@ -4,6 +4,8 @@ import (
 	"fmt"
 )
 
+const volumeSizeStepMB = 1024
+
 type (
 	VolumeEntity struct {
 		ID      int    `json:"id,omitempty"`
@@ -84,9 +86,59 @@ func (c *SSClient) UpdateVolumeAndWait(
 	return c.waitVolume(serverID, taskWrap.ID)
 }
 
+func (c *SSClient) ResizeVolume(
+	serverID string,
+	volumeID int,
+	size int,
+	force bool,
+) (*VolumeEntity, error) {
+	server, err := c.GetServer(serverID)
+	if err != nil {
+		return nil, err
+	}
+	volume, err := c.GetVolume(serverID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	if size == volume.Size {
+		return volume, nil
+	}
+	if size < volume.Size && !force {
+		return nil, fmt.Errorf(
+			"volume '%d' can't be shrunk from %d MB to %d MB without force",
+			volumeID, volume.Size, size,
+		)
+	}
+	if size%volumeSizeStepMB != 0 {
+		return nil, fmt.Errorf("volume size %d MB isn't a multiple of %d MB", size, volumeSizeStepMB)
+	}
+
+	location, err := c.GetCachedLocation(server.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	minSize := location.AdditionalVolumeMin
+	if len(server.Volumes) > 0 && server.Volumes[0].ID == volumeID {
+		minSize = location.SystemVolumeMin
+	}
+	if size < minSize {
+		return nil, fmt.Errorf(
+			"volume size %d MB is less than minimum %d MB in location '%s'",
+			size, minSize, location.ID,
+		)
+	}
+	if location.VolumeMax > 0 && size > location.VolumeMax {
+		return nil, fmt.Errorf(
+			"volume size %d MB exceeds maximum %d MB in location '%s'",
+			size, location.VolumeMax, location.ID,
+		)
+	}
+
+	return c.UpdateVolumeAndWait(serverID, volumeID, volume.Name, size)
+}
+
 func (c *SSClient) DeleteVolume(serverID string, volumeID int) error {
-	url := getVolumeURL(serverID, volumeID)
-	if _, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{}); err != nil {
+	if _, err := c.deleteVolume(serverID, volumeID); err != nil {
 		return err
 	}
 	if _, err := c.waitServerActive(serverID); err != nil {
@@ -95,6 +147,26 @@ func (c *SSClient) DeleteVolume(serverID string, volumeID int) error {
 	return nil
 }
 
+func (c *SSClient) DeleteVolumeAndWait(serverID string, volumeID int) error {
+	taskWrap, err := c.deleteVolume(serverID, volumeID)
+	if err != nil {
+		return err
+	}
+	return c.waitResourceDeletion(taskWrap.ID, func() error {
+		_, err := c.GetVolume(serverID, volumeID)
+		return err
+	})
+}
+
+func (c *SSClient) deleteVolume(serverID string, volumeID int) (*TaskIDWrap, error) {
+	url := getVolumeURL(serverID, volumeID)
+	resp, err := makeRequest(c.client, url, methodDelete, nil, &TaskIDWrap{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*TaskIDWrap), nil
+}
+
 func getVolumeURL(serverID string, volumeID int) string {
 	volumesBaseURL := getVolumesBaseURL(serverID)
 	return fmt.Sprintf("%s/%d", volumesBaseURL, volumeID)